@@ -0,0 +1,86 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/nightmarlin/aoc2022/aoc"
+)
+
+// Several AoC accounts can be set up side by side, so that solutions can be
+// checked against more than one person's inputs. Each account is a session file
+// named after it in the accounts directory, and its inputs, metadata and ledger
+// are kept in a folder of the same name under <inputs>/accounts.
+
+const accountsFolder = "accounts"
+
+var accountNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// An account is one of the configured AoC users.
+type account struct {
+	Name        string
+	SessionFile string
+	LocalFolder string
+}
+
+// account returns the named account, which need not have a session file yet.
+func (a *app) account(name string) (account, error) {
+	if !accountNamePattern.MatchString(name) {
+		return account{}, withExitCode(
+			exitConfig,
+			fmt.Errorf("invalid account name %q, only letters, numbers, '-' and '_' are allowed", name),
+		)
+	}
+	if a.accountsDir == "" {
+		return account{}, withExitCode(exitConfig, errors.New("no accounts directory, set one with -accounts-dir"))
+	}
+
+	return account{
+		Name:        name,
+		SessionFile: filepath.Join(a.accountsDir, name),
+		LocalFolder: filepath.Join(a.localFolder, accountsFolder, name),
+	}, nil
+}
+
+// accounts lists every configured account, in order of name.
+func (a *app) accounts() ([]account, error) {
+	entries, err := os.ReadDir(a.accountsDir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, withExitCode(exitConfig, fmt.Errorf("failed to list accounts: %w", err))
+	}
+
+	var res []account
+	for _, e := range entries {
+		if e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+
+		acc, err := a.account(e.Name())
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, acc)
+	}
+
+	if len(res) == 0 {
+		return nil, withExitCode(
+			exitConfig,
+			fmt.Errorf("no accounts set up, save each account's session cookie in %s as a file named after the account", a.accountsDir),
+		)
+	}
+	return res, nil
+}
+
+// accountFetcher initialises an aoc.Fetcher using the account's session and
+// local folder.
+func (a *app) accountFetcher(acc account) (aoc.Fetcher, error) {
+	session, err := aoc.ReadSessionFile(acc.SessionFile)
+	if err != nil {
+		return aoc.Fetcher{}, withExitCode(exitConfig, fmt.Errorf("account %s: %w", acc.Name, err))
+	}
+	return a.newFetcher(session, acc.LocalFolder)
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nightmarlin/aoc2022/fakeaoc"
+)
+
+const day01ExampleInput = "1000\n2000\n3000\n\n4000\n\n5000\n6000\n\n7000\n8000\n9000\n\n10000\n"
+
+func TestRunAccounts(t *testing.T) {
+	var (
+		accountsDir = t.TempDir()
+		inputs      = t.TempDir()
+	)
+
+	// Each account has the example as its input, but bob's ledger says the
+	// answer to part one is something else.
+	ledgers := map[string]string{
+		"alice": `{"2022/1": {"1": {"attempts": [], "correct": "24000"}}}`,
+		"bob":   `{"2022/1": {"1": {"attempts": [], "correct": "23000"}}}`,
+	}
+	for name, ledger := range ledgers {
+		require.NoError(t, os.WriteFile(filepath.Join(accountsDir, name), []byte("cookie-"+name), 0o600))
+
+		folder := filepath.Join(inputs, accountsFolder, name)
+		require.NoError(t, os.MkdirAll(filepath.Join(folder, "2022"), 0o700))
+		require.NoError(t, os.WriteFile(filepath.Join(folder, "2022", "01"), []byte(day01ExampleInput), 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(folder, "ledger.json"), []byte(ledger), 0o600))
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI(
+		context.Background(),
+		[]string{"-accounts-dir", accountsDir, "-inputs", inputs, "run", "1", "-accounts"},
+		&stdout,
+		&stderr,
+	)
+	assert.Equal(t, exitMismatch, code, stderr.String())
+
+	out := stdout.String()
+	assert.Contains(t, out, "PART 1")
+	assert.Regexp(t, `alice\s+24000 \(ok\)\s+45000 \(unverified\)`, out)
+	assert.Regexp(t, `bob\s+24000 \(WRONG\)\s+45000 \(unverified\)`, out)
+}
+
+func TestRunAccountsSessionRejected(t *testing.T) {
+	srv := httptest.NewServer(fakeaoc.New(fakeaoc.Config{
+		Fixtures: "fakeaoc/testdata",
+		Sessions: map[string]string{"cookie-alice": "Alice"},
+	}))
+	defer srv.Close()
+
+	var (
+		accountsDir = t.TempDir()
+		inputs      = t.TempDir()
+	)
+
+	// alice's input is cached, but carol's has to be fetched with a session
+	// that fakeaoc doesn't know.
+	for _, name := range []string{"alice", "carol"} {
+		require.NoError(t, os.WriteFile(filepath.Join(accountsDir, name), []byte("cookie-"+name), 0o600))
+	}
+	folder := filepath.Join(inputs, accountsFolder, "alice", "2022")
+	require.NoError(t, os.MkdirAll(folder, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(folder, "01"), []byte(day01ExampleInput), 0o600))
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI(
+		context.Background(),
+		[]string{"-base-url", srv.URL, "-accounts-dir", accountsDir, "-inputs", inputs, "run", "1", "-accounts"},
+		&stdout,
+		&stderr,
+	)
+	assert.Equal(t, exitConfig, code, stderr.String())
+
+	out := stdout.String()
+	assert.Regexp(t, `alice\s+24000 \(unverified\)\s+45000 \(unverified\)`, out)
+	assert.Regexp(t, `carol\s+\(failed: .*session cookie.*\)`, out)
+	assert.Contains(t, stderr.String(), "account carol")
+}
+
+func TestAccountNames(t *testing.T) {
+	a := &app{accountsDir: "accounts", localFolder: "inputs"}
+
+	acc, err := a.account("alice_2")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("accounts", "alice_2"), acc.SessionFile)
+	assert.Equal(t, filepath.Join("inputs", accountsFolder, "alice_2"), acc.LocalFolder)
+
+	for _, name := range []string{"", "../bob", "bob/2022", ".hidden"} {
+		_, err := a.account(name)
+		assert.Equal(t, exitConfig, exitCodeFor(err), "%q", name)
+	}
+}
@@ -0,0 +1,106 @@
+package aoc
+
+import (
+	"math/big"
+	"strings"
+)
+
+// An Answer is the result of running one part of a Solution. AoC only ever
+// accepts answers as text, but numeric answers keep hold of their value so that
+// they can be compared against one another.
+//
+// The zero Answer is empty, and is what a Solution should return alongside an
+// error.
+type Answer struct {
+	num *big.Int
+	str string
+}
+
+// IntAnswer creates a numeric Answer from an int.
+func IntAnswer(i int) Answer {
+	return Answer{num: big.NewInt(int64(i))}
+}
+
+// BigAnswer creates a numeric Answer from a big.Int, for those puzzles whose
+// answers overflow an int. The value is copied.
+func BigAnswer(b *big.Int) Answer {
+	if b == nil {
+		return Answer{}
+	}
+	return Answer{num: new(big.Int).Set(b)}
+}
+
+// StringAnswer creates a textual Answer, such as a sequence of crate labels.
+func StringAnswer(s string) Answer {
+	return Answer{str: s}
+}
+
+// ParseAnswer converts the text form of an Answer back into an Answer. Anything
+// that looks like an integer becomes a numeric Answer.
+func ParseAnswer(s string) Answer {
+	s = strings.TrimSpace(s)
+	if b, ok := new(big.Int).SetString(s, 10); ok {
+		return Answer{num: b}
+	}
+	return StringAnswer(s)
+}
+
+// IsZero reports whether the Answer is empty.
+func (a Answer) IsZero() bool { return a.num == nil && a.str == "" }
+
+// IsNumeric reports whether the Answer holds a number.
+func (a Answer) IsNumeric() bool { return a.num != nil }
+
+// Int returns the Answer as an int, if it is numeric and fits.
+func (a Answer) Int() (int, bool) {
+	if a.num == nil || !a.num.IsInt64() {
+		return 0, false
+	}
+	i := a.num.Int64()
+	if int64(int(i)) != i {
+		return 0, false
+	}
+	return int(i), true
+}
+
+// Big returns a copy of the Answer as a big.Int, if it is numeric.
+func (a Answer) Big() (*big.Int, bool) {
+	if a.num == nil {
+		return nil, false
+	}
+	return new(big.Int).Set(a.num), true
+}
+
+// String returns the Answer in the form it would be submitted to AoC.
+func (a Answer) String() string {
+	if a.num != nil {
+		return a.num.String()
+	}
+	return a.str
+}
+
+// Equal reports whether two Answers would be submitted as the same text.
+func (a Answer) Equal(o Answer) bool {
+	return a.String() == o.String()
+}
+
+// Cmp compares two numeric Answers, returning -1, 0 or +1 as a is less than,
+// equal to or greater than o. ok is false if either Answer is not numeric.
+func (a Answer) Cmp(o Answer) (res int, ok bool) {
+	if a.num == nil || o.num == nil {
+		return 0, false
+	}
+	return a.num.Cmp(o.num), true
+}
+
+// MarshalText implements encoding.TextMarshaler so Answers can be stored as
+// plain strings.
+func (a Answer) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using ParseAnswer.
+func (a *Answer) UnmarshalText(text []byte) error {
+	*a = ParseAnswer(string(text))
+	return nil
+}
@@ -0,0 +1,49 @@
+package aoc
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAnswer(t *testing.T) {
+	huge, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+
+	testTable := []struct {
+		Name string
+
+		Input   string
+		Want    Answer
+		Numeric bool
+	}{
+		{Name: "int", Input: "24000", Want: IntAnswer(24000), Numeric: true},
+		{Name: "negative int", Input: "-12", Want: IntAnswer(-12), Numeric: true},
+		{Name: "surrounding whitespace", Input: " 157\n", Want: IntAnswer(157), Numeric: true},
+		{Name: "big int", Input: huge.String(), Want: BigAnswer(huge), Numeric: true},
+		{Name: "crate letters", Input: "CMZ", Want: StringAnswer("CMZ")},
+	}
+
+	for _, entry := range testTable {
+		entry := entry
+		t.Run(
+			entry.Name,
+			func(t *testing.T) {
+				t.Parallel()
+
+				got := ParseAnswer(entry.Input)
+				assert.True(t, got.Equal(entry.Want))
+				assert.Equal(t, entry.Numeric, got.IsNumeric())
+			},
+		)
+	}
+}
+
+func TestAnswerCmp(t *testing.T) {
+	res, ok := IntAnswer(10).Cmp(IntAnswer(20))
+	assert.True(t, ok)
+	assert.Equal(t, -1, res)
+
+	_, ok = IntAnswer(10).Cmp(StringAnswer("CMZ"))
+	assert.False(t, ok)
+}
@@ -0,0 +1,71 @@
+// Package aoctest provides helpers for tests and benchmarks that run against
+// real puzzle inputs.
+package aoctest
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/nightmarlin/aoc2022/aoc"
+)
+
+// InputFolder returns the folder inputs are cached in. It honours LOCAL_FOLDER
+// in the same way as the CLI, with relative paths being resolved from the root
+// of the module rather than the directory of the package under test.
+func InputFolder() string {
+	folder := os.Getenv("LOCAL_FOLDER")
+	if folder == "" {
+		folder = "inputs"
+	}
+	if filepath.IsAbs(folder) {
+		return folder
+	}
+	return filepath.Join(moduleRoot(), folder)
+}
+
+// moduleRoot finds the closest parent directory containing a go.mod, falling
+// back to the working directory.
+func moduleRoot() string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "."
+	}
+
+	for dir := wd; ; dir = filepath.Dir(dir) {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir
+		}
+		if filepath.Dir(dir) == dir {
+			return wd
+		}
+	}
+}
+
+// Input loads the cached input for the Puzzle. Inputs are personal and aren't
+// checked in, so if it hasn't been fetched yet (with `go run . fetch <day>`)
+// the test or benchmark is skipped. Encrypted inputs are read when
+// AOC_INPUT_PASSPHRASE is set, as with the CLI.
+func Input(tb testing.TB, p aoc.Puzzle) string {
+	tb.Helper()
+
+	var store aoc.InputStore = aoc.NewFileStore(zap.NewNop(), InputFolder())
+	if passphrase := os.Getenv("AOC_INPUT_PASSPHRASE"); passphrase != "" {
+		var err error
+		if store, err = aoc.NewEncryptedStore(store, passphrase); err != nil {
+			tb.Fatalf("failed to init encrypted input store: %s", err)
+		}
+	}
+
+	input, err := store.Load(p)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		tb.Skipf("no cached input for %s in %s", p, InputFolder())
+	case err != nil:
+		tb.Fatalf("failed to read input for %s: %s", p, err)
+	}
+	return input
+}
@@ -0,0 +1,34 @@
+package aoctest
+
+import "io"
+
+// RepeatReader returns a reader producing s n times over. The whole input is
+// never held in memory, so it can stand in for inputs far larger than any real
+// one when testing a registry.StreamingSolution.
+func RepeatReader(s string, n int) io.Reader {
+	return &repeatReader{s: s, left: n}
+}
+
+type repeatReader struct {
+	s    string
+	left int // left is how many more times s will be read, including the current one.
+	off  int // off is how much of the current s has been read.
+}
+
+func (r *repeatReader) Read(p []byte) (int, error) {
+	if r.left <= 0 || r.s == "" {
+		return 0, io.EOF
+	}
+
+	n := 0
+	for n < len(p) && r.left > 0 {
+		c := copy(p[n:], r.s[r.off:])
+		n += c
+		r.off += c
+		if r.off == len(r.s) {
+			r.off = 0
+			r.left--
+		}
+	}
+	return n, nil
+}
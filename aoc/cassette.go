@@ -0,0 +1,256 @@
+package aoc
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// A Cassette is an http.RoundTripper that records the requests a Fetcher makes
+// and the responses AoC gives, or replays responses recorded earlier. With
+// WithTransport it lets tests run against saved responses without contacting
+// AoC each time.
+//
+// Cassettes are saved as JSON, so they can also be written by hand. The
+// session cookie is scrubbed from everything that's recorded: the Cookie and
+// Set-Cookie headers aren't saved at all, and anywhere else the cookie appears
+// it's replaced with scrubbedSession.
+type Cassette struct {
+	path string
+	mode CassetteMode
+	next http.RoundTripper
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool // used marks the interactions that have been replayed.
+}
+
+// A CassetteMode says whether a Cassette records or replays.
+type CassetteMode int
+
+const (
+	// CassetteReplay answers requests from the Cassette, without sending them.
+	CassetteReplay CassetteMode = iota
+
+	// CassetteRecord sends requests on, and records them and their responses.
+	CassetteRecord
+)
+
+// An Interaction is a single request and its response, as saved in a Cassette.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// A RecordedRequest identifies a request. Requests are replayed by matching all
+// of its fields.
+type RecordedRequest struct {
+	Method string `json:"method"`
+
+	// URI is the request's path and query, such as "/2022/day/1". The host is
+	// left out so that replaying doesn't depend on the base URL.
+	URI string `json:"uri"`
+
+	// Body is the request's body, which for submissions is the encoded form.
+	Body string `json:"body,omitempty"`
+}
+
+// A RecordedResponse is everything of a response that the Fetcher reads.
+type RecordedResponse struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body"`
+}
+
+// ErrCassetteMiss is returned, as a PermanentError, when a replaying Cassette
+// has no response left for a request. Replaying again won't find one either, so
+// it's never retried.
+var ErrCassetteMiss = errors.New("no recorded response")
+
+// scrubbedSession replaces the session cookie wherever it appears in a
+// recording.
+const scrubbedSession = "SCRUBBED"
+
+// recordedHeaders are the response headers a Cassette saves. The rest, such as
+// Date and Set-Cookie, either change on every request or mustn't be saved.
+var recordedHeaders = []string{"Content-Type", "Location", "Retry-After"}
+
+type cassetteFile struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// OpenCassette opens the Cassette saved at path. When recording, requests are
+// sent on with next, or http.DefaultTransport if it's nil, and the recording
+// replaces whatever was at path once Save is called.
+func OpenCassette(path string, mode CassetteMode, next http.RoundTripper) (*Cassette, error) {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	c := &Cassette{path: path, mode: mode, next: next}
+
+	if mode == CassetteRecord {
+		return c, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cassette: %w", err)
+	}
+
+	var file cassetteFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse cassette %s: %w", path, err)
+	}
+
+	c.interactions = file.Interactions
+	c.used = make([]bool, len(file.Interactions))
+	return c, nil
+}
+
+// WithTransport sends the Fetcher's requests through rt, such as a Cassette.
+func WithTransport(rt http.RoundTripper) FetcherOption {
+	return func(f *Fetcher) {
+		f.client.Transport = rt
+	}
+}
+
+func (c *Cassette) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	recorded := RecordedRequest{Method: req.Method, URI: req.URL.RequestURI(), Body: body}
+
+	if c.mode == CassetteRecord {
+		// RoundTrip mustn't change req, so a copy with the body put back is sent
+		// on instead.
+		sent := req.Clone(req.Context())
+		sent.Body = http.NoBody
+		if body != "" {
+			sent.Body = io.NopCloser(strings.NewReader(body))
+		}
+		return c.record(sent, recorded)
+	}
+	return c.replay(req, recorded)
+}
+
+// replay answers with the first interaction matching the request that hasn't
+// been replayed yet, so a request made twice gets the responses in the order
+// they were recorded.
+func (c *Cassette) replay(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, in := range c.interactions {
+		if c.used[i] || in.Request != recorded {
+			continue
+		}
+		c.used[i] = true
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.Status, http.StatusText(in.Response.Status)),
+			StatusCode:    in.Response.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        in.Response.Header.Clone(),
+			Body:          io.NopCloser(strings.NewReader(in.Response.Body)),
+			ContentLength: int64(len(in.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, PermanentError{
+		Err: fmt.Errorf("%w for %s %s in %s", ErrCassetteMiss, recorded.Method, recorded.URI, c.path),
+	}
+}
+
+func (c *Cassette) record(req *http.Request, recorded RecordedRequest) (*http.Response, error) {
+	res, err := c.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(res.Body)
+	_ = res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+
+	header := make(http.Header)
+	for _, name := range recordedHeaders {
+		if values := res.Header.Values(name); len(values) > 0 {
+			header[name] = values
+		}
+	}
+	in := Interaction{
+		Request:  recorded,
+		Response: RecordedResponse{Status: res.StatusCode, Header: header, Body: string(body)},
+	}
+	if session, err := req.Cookie("session"); err == nil && session.Value != "" {
+		in = scrubInteraction(in, session.Value)
+	}
+
+	c.mu.Lock()
+	c.interactions = append(c.interactions, in)
+	c.mu.Unlock()
+
+	return res, nil
+}
+
+// scrubInteraction replaces the session cookie wherever it appears in the
+// interaction.
+func scrubInteraction(in Interaction, session string) Interaction {
+	scrub := func(s string) string { return strings.ReplaceAll(s, session, scrubbedSession) }
+
+	in.Request.URI = scrub(in.Request.URI)
+	in.Request.Body = scrub(in.Request.Body)
+	in.Response.Body = scrub(in.Response.Body)
+	for name, values := range in.Response.Header {
+		for i := range values {
+			values[i] = scrub(values[i])
+		}
+		in.Response.Header[name] = values
+	}
+	return in
+}
+
+// Save writes what a recording Cassette has recorded to its path. It does
+// nothing when replaying.
+func (c *Cassette) Save() error {
+	if c.mode != CassetteRecord {
+		return nil
+	}
+
+	c.mu.Lock()
+	data, err := json.MarshalIndent(cassetteFile{Interactions: c.interactions}, "", "  ")
+	c.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to marshal cassette: %w", err)
+	}
+
+	if err := writeFileAtomic(c.path, append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to save cassette: %w", err)
+	}
+	return nil
+}
+
+// readRequestBody reads and closes the request's body, if it has one.
+func readRequestBody(req *http.Request) (string, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return "", nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return "", fmt.Errorf("failed to read request body: %w", err)
+	}
+	return string(body), nil
+}
@@ -0,0 +1,179 @@
+package aoc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// recordCassettes is set to record the cassettes in testdata/cassettes against
+// AoC with the session in SESSION_COOKIE, rather than replaying them. The
+// cassettes checked in weren't recorded from AoC: they were written by hand in
+// the shape of AoC's pages, as the other testdata was, so they only check the
+// Fetcher against our understanding of AoC until they're recorded. Recording
+// submit.json submits its answers for real, so it needs an account that hasn't
+// solved 2022 day 1 yet.
+var recordCassettes = os.Getenv("AOC_RECORD_CASSETTES") != ""
+
+// cassetteFetcher creates a Fetcher whose requests are answered by the named
+// cassette in testdata/cassettes.
+func cassetteFetcher(t *testing.T, name string) Fetcher {
+	t.Helper()
+
+	var (
+		path    = filepath.Join("testdata", "cassettes", name+".json")
+		mode    = CassetteReplay
+		session = testSession
+		opts    = []FetcherOption{WithMinInterval(0)}
+	)
+	if recordCassettes {
+		mode = CassetteRecord
+		session = os.Getenv("SESSION_COOKIE")
+		opts = nil
+	}
+
+	c, err := OpenCassette(path, mode, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, c.Save()) })
+
+	f, err := NewFetcher(zap.NewNop(), session, t.TempDir(), append(opts, WithTransport(c))...)
+	require.NoError(t, err)
+	return f
+}
+
+func TestCassettePuzzlePage(t *testing.T) {
+	var (
+		ctx = context.Background()
+		f   = cassetteFetcher(t, "puzzle")
+		p   = Puzzle{Year: 2022, Day: 1}
+	)
+
+	examples, err := f.FetchExamples(ctx, p)
+	require.NoError(t, err)
+	require.Len(t, examples, 2)
+	assert.Equal(t, day01ExampleInput, examples[0].Input)
+	assert.Equal(t, "24000", examples[0].Answer.String())
+	assert.Equal(t, "45000", examples[1].Answer.String())
+
+	desc, err := f.FetchDescription(ctx, p)
+	require.NoError(t, err)
+	assert.Contains(t, desc, "--- Day 1: Calorie Counting ---")
+	assert.Contains(t, desc, partTwoHeading)
+}
+
+func TestCassetteSubmit(t *testing.T) {
+	var (
+		ctx = context.Background()
+		f   = cassetteFetcher(t, "submit")
+		p   = Puzzle{Year: 2022, Day: 1}
+	)
+
+	testTable := []struct {
+		Part    int
+		Answer  Answer
+		Verdict Verdict
+		Wait    time.Duration
+	}{
+		{Part: 1, Answer: IntAnswer(30000), Verdict: VerdictTooHigh, Wait: time.Minute},
+		{Part: 1, Answer: IntAnswer(24000), Verdict: VerdictRateLimited, Wait: 35 * time.Second},
+		{Part: 1, Answer: IntAnswer(24000), Verdict: VerdictCorrect},
+		{Part: 2, Answer: IntAnswer(45000), Verdict: VerdictCorrect},
+	}
+
+	// The submissions depend on each other, so they're made in order rather
+	// than as subtests.
+	for _, entry := range testTable {
+		if recordCassettes && entry.Verdict == VerdictCorrect {
+			time.Sleep(time.Minute)
+		}
+
+		res, err := f.Submit(ctx, p, entry.Part, entry.Answer)
+		require.NoError(t, err)
+		assert.Equal(t, entry.Verdict, res.Verdict, res.Message)
+		assert.Equal(t, entry.Wait, res.Wait)
+	}
+}
+
+func TestCassetteLeaderboard(t *testing.T) {
+	var (
+		ctx = context.Background()
+		f   = cassetteFetcher(t, "leaderboard")
+	)
+
+	board, err := f.FetchLeaderboard(ctx, 2022, 101)
+	require.NoError(t, err)
+	assert.Equal(t, 101, board.OwnerID)
+	assert.Len(t, board.Members, 3)
+
+	_, err = f.FetchLeaderboard(ctx, 2022, 202)
+	assert.Error(t, err, "leaderboards that redirect should fail")
+}
+
+func TestCassetteRecord(t *testing.T) {
+	const session = "53616c7465645f5f-secret"
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c, err := r.Cookie("session")
+		require.NoError(t, err)
+
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: c.Value})
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("X-Session", c.Value)
+		_, _ = fmt.Fprintf(w, "input for %s\n", c.Value)
+	}))
+	defer srv.Close()
+
+	root, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	var (
+		ctx  = context.Background()
+		path = filepath.Join(t.TempDir(), "input.json")
+		p    = Puzzle{Year: 2022, Day: 1}
+	)
+
+	recorder, err := OpenCassette(path, CassetteRecord, nil)
+	require.NoError(t, err)
+
+	f, err := NewFetcher(zap.NewNop(), session, t.TempDir(), WithBaseURL(root), WithMinInterval(0), WithTransport(recorder))
+	require.NoError(t, err)
+
+	input, err := f.FetchInput(ctx, p)
+	require.NoError(t, err)
+	assert.Equal(t, "input for "+session+"\n", input)
+	require.NoError(t, recorder.Save())
+
+	saved, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(saved), session, "the session should be scrubbed")
+	assert.NotContains(t, string(saved), "X-Session", "only known headers should be saved")
+	assert.Contains(t, string(saved), `"uri": "/2022/day/1/input"`)
+
+	player, err := OpenCassette(path, CassetteReplay, nil)
+	require.NoError(t, err)
+
+	// The default retries are kept, as a miss shouldn't be retried.
+	f, err = NewFetcher(zap.NewNop(), testSession, t.TempDir(), WithMinInterval(0), WithTransport(player))
+	require.NoError(t, err)
+
+	input, err = f.FetchInput(ctx, p)
+	require.NoError(t, err)
+	assert.Equal(t, "input for "+scrubbedSession+"\n", input)
+
+	start := time.Now()
+	_, err = f.FetchPuzzlePage(ctx, p)
+	assert.ErrorIs(t, err, ErrCassetteMiss)
+	assert.ErrorAs(t, err, new(PermanentError))
+	assert.NotErrorIs(t, err, ErrNetwork)
+	assert.Less(t, time.Since(start), DefaultRetryBase, "a miss shouldn't be retried")
+}
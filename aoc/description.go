@@ -0,0 +1,85 @@
+package aoc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// partTwoHeading starts the description of part two, which only appears once
+// part one has been solved.
+const partTwoHeading = "--- Part Two ---"
+
+var descriptionPattern = regexp.MustCompile(`(?s)<article class="day-desc">(.*?)</article>`)
+
+// FetchDescription returns the description of the day's puzzle, rendered as
+// Markdown. It is cached beside the input, and fetched again while the cached
+// version is missing part two, as that part may have been unlocked since.
+func (f Fetcher) FetchDescription(ctx context.Context, p Puzzle) (string, error) {
+	cached, err := f.loadDescription(p)
+	switch {
+	case err != nil:
+		f.log.Warn("failed to load cached description, fetching from aoc", zap.Error(err))
+	case strings.Contains(cached, partTwoHeading):
+		return cached, nil
+	case cached != "":
+		f.log.Info("cached description is missing part two, checking aoc for it")
+	}
+
+	page, err := f.FetchPuzzlePage(ctx, p)
+	if err != nil {
+		if cached != "" {
+			f.log.Warn("failed to refresh description, using cached version", zap.Error(err))
+			return cached, nil
+		}
+		return "", err
+	}
+
+	desc := parseDescription(page, f.root)
+	if desc == "" {
+		return "", fmt.Errorf("no description found on the puzzle page for %s", p)
+	}
+
+	if err := f.saveDescription(p, desc); err != nil {
+		f.log.Warn("failed to save description to local folder", zap.Error(err))
+	}
+	return desc, nil
+}
+
+// parseDescription renders each part's description on the puzzle page as
+// Markdown, returning "" if there are none.
+func parseDescription(page string, root *url.URL) string {
+	var parts []string
+	for _, m := range descriptionPattern.FindAllStringSubmatch(page, -1) {
+		parts = append(parts, renderMarkdown(m[1], root))
+	}
+	return strings.Join(parts, "\n")
+}
+
+func (f Fetcher) descriptionFileName(p Puzzle) string {
+	return f.inputFileName(p) + ".md"
+}
+
+func (f Fetcher) loadDescription(p Puzzle) (string, error) {
+	data, err := os.ReadFile(f.descriptionFileName(p))
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return "", nil
+	case err != nil:
+		return "", fmt.Errorf("failed to read description file: %w", err)
+	}
+	return string(data), nil
+}
+
+func (f Fetcher) saveDescription(p Puzzle, desc string) error {
+	if err := writeFileAtomic(f.descriptionFileName(p), []byte(desc)); err != nil {
+		return fmt.Errorf("failed to write description file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package aoc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	root, err := url.Parse(RootURL)
+	require.NoError(t, err)
+
+	testTable := []struct {
+		Name string
+
+		HTML string
+		Want string
+	}{
+		{
+			Name: "heading",
+			HTML: `<h2>--- Day 1: Calorie Counting ---</h2>`,
+			Want: "## --- Day 1: Calorie Counting ---\n",
+		},
+		{
+			Name: "emphasised code",
+			HTML: `<p>a total of <code><em>24000</em></code> or <em><code>45000</code></em> <em>Calories</em></p>`,
+			Want: "a total of *`24000`* or *`45000`* *Calories*\n",
+		},
+		{
+			Name: "relative link",
+			HTML: `<p>need <a href="/2018/day/25">magical energy</a>.</p>`,
+			Want: "need [magical energy](https://adventofcode.com/2018/day/25).\n",
+		},
+		{
+			Name: "code block keeps whitespace and entities",
+			HTML: "<pre><code>a -&gt; b\n  c &amp; d\n</code></pre>",
+			Want: "```\na -> b\n  c & d\n```\n",
+		},
+		{
+			Name: "list",
+			HTML: "<ul>\n<li>one</li>\n<li>two</li>\n</ul>\n<p>after</p>",
+			Want: "- one\n- two\n\nafter\n",
+		},
+		{
+			Name: "wrapped paragraph",
+			HTML: "<p>" + strings.Repeat("word ", 20) + "</p>",
+			Want: strings.TrimSpace(strings.Repeat("word ", 16)) + "\n" + strings.TrimSpace(strings.Repeat("word ", 4)) + "\n",
+		},
+		{
+			Name: "unknown tags keep their text",
+			HTML: `<p><span title="easter egg">hidden</span> text</p>`,
+			Want: "hidden text\n",
+		},
+	}
+
+	for _, entry := range testTable {
+		entry := entry
+		t.Run(
+			entry.Name,
+			func(t *testing.T) {
+				t.Parallel()
+
+				assert.Equal(t, entry.Want, renderMarkdown(entry.HTML, root))
+			},
+		)
+	}
+}
+
+func TestFetchDescription(t *testing.T) {
+	page, err := os.ReadFile("testdata/day01.html")
+	require.NoError(t, err)
+	partOneOnly := string(page[:strings.Index(string(page), `<article class="day-desc"><h2 id="part2">`)])
+
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "/2022/day/1", r.URL.Path)
+		if requests == 1 {
+			_, _ = w.Write([]byte(partOneOnly))
+			return
+		}
+		_, _ = w.Write(page)
+	}))
+	defer srv.Close()
+
+	f := testFetcher(t, srv)
+	p := Puzzle{Year: 2022, Day: 1}
+
+	desc, err := f.FetchDescription(context.Background(), p)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(desc, "## --- Day 1: Calorie Counting ---\n"))
+	assert.NotContains(t, desc, partTwoHeading)
+	assert.NotContains(t, desc, "Your puzzle answer was", "text outside the descriptions should be left out")
+
+	desc, err = f.FetchDescription(context.Background(), p)
+	require.NoError(t, err)
+	assert.Contains(t, desc, "## --- Part Two ---")
+	assert.Equal(t, 2, requests, "a description without part two should be fetched again")
+
+	_, err = f.FetchDescription(context.Background(), p)
+	require.NoError(t, err)
+	assert.Equal(t, 2, requests, "a description with part two should be served from the cache")
+	assert.FileExists(t, f.descriptionFileName(p))
+}
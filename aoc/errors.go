@@ -0,0 +1,181 @@
+package aoc
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sentinel errors for each class of failure when talking to AoC. The typed
+// errors below match them with errors.Is, and carry the details for use with
+// errors.As.
+var (
+	ErrNotUnlocked    = errors.New("puzzle not yet unlocked")
+	ErrSessionInvalid = errors.New("session expired or invalid")
+	ErrRateLimited    = errors.New("rate limited")
+	ErrServer         = errors.New("aoc server error")
+	ErrNetwork        = errors.New("network error")
+	ErrOffline        = errors.New("offline")
+	ErrNoSession      = errors.New("no session cookie")
+)
+
+// NotUnlockedError is returned when asking AoC for a Puzzle that hasn't been
+// released yet.
+type NotUnlockedError struct {
+	Puzzle    Puzzle
+	UnlocksAt time.Time
+	Remaining time.Duration
+}
+
+func (e NotUnlockedError) Error() string {
+	if e.Puzzle == (Puzzle{}) {
+		// AoC told us rather than our own clock, so we don't know the details.
+		return "the puzzle is not unlocked yet"
+	}
+	return fmt.Sprintf("%s is not unlocked yet, it unlocks in %s", e.Puzzle, e.Remaining.Round(time.Second))
+}
+
+func (e NotUnlockedError) Is(target error) bool { return target == ErrNotUnlocked }
+
+// SessionError is returned when AoC doesn't accept the session cookie, which
+// usually means it has expired.
+type SessionError struct {
+	// StatusCode is that of the response, or 0 if AoC served the page as though
+	// nobody was logged in.
+	StatusCode int
+}
+
+func (e SessionError) Error() string {
+	const advice = "it has probably expired - log in again and copy a fresh one from your browser"
+	if e.StatusCode == 0 {
+		// AoC served the page, but not to a logged in user.
+		return "aoc doesn't recognise the session cookie, " + advice
+	}
+	return fmt.Sprintf("aoc rejected the session cookie (status %d), %s", e.StatusCode, advice)
+}
+
+func (e SessionError) Is(target error) bool { return target == ErrSessionInvalid }
+
+// RateLimitedError is returned when AoC responds with 429 Too Many Requests.
+type RateLimitedError struct {
+	// RetryAfter is how long AoC asked us to wait, if it said.
+	RetryAfter time.Duration
+}
+
+func (e RateLimitedError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("rate limited by aoc, retry after %s", e.RetryAfter)
+	}
+	return "rate limited by aoc"
+}
+
+func (e RateLimitedError) Is(target error) bool { return target == ErrRateLimited }
+
+// ServerError is returned for 5xx responses.
+type ServerError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e ServerError) Error() string {
+	return fmt.Sprintf("aoc server error, got status %d: %s", e.StatusCode, e.Body)
+}
+
+func (e ServerError) Is(target error) bool { return target == ErrServer }
+
+// NetworkError is returned when no response was received at all.
+type NetworkError struct {
+	Err error
+}
+
+func (e NetworkError) Error() string        { return fmt.Sprintf("network error: %s", e.Err) }
+func (e NetworkError) Unwrap() error        { return e.Err }
+func (e NetworkError) Is(target error) bool { return target == ErrNetwork }
+
+// OfflineError is returned instead of making a request when the Fetcher is
+// offline, which means whatever was asked for isn't saved locally.
+type OfflineError struct {
+	URL string
+}
+
+func (e OfflineError) Error() string {
+	return fmt.Sprintf("working offline, and %s isn't saved locally", e.URL)
+}
+
+func (e OfflineError) Is(target error) bool { return target == ErrOffline }
+
+// PermanentError is returned by a transport given to WithTransport for a
+// failure that trying again can't fix. Unlike other failures to get a
+// response, it isn't treated as a NetworkError, so it's never retried.
+type PermanentError struct {
+	Err error
+}
+
+func (e PermanentError) Error() string { return e.Err.Error() }
+func (e PermanentError) Unwrap() error { return e.Err }
+
+// StatusError is returned for any other unexpected response.
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e StatusError) Error() string {
+	return fmt.Sprintf("got status %d, wanted 200. body: %s", e.StatusCode, e.Body)
+}
+
+// isTransient reports whether the request that failed with err is worth trying
+// again.
+func isTransient(err error) bool {
+	return errors.Is(err, ErrRateLimited) || errors.Is(err, ErrServer) || errors.Is(err, ErrNetwork)
+}
+
+// maxErrorBodyLen limits how much of a response body is kept in an error, as
+// AoC's error pages are full HTML documents.
+const maxErrorBodyLen = 200
+
+// classifyResponse turns a non-200 response into one of the typed errors.
+func classifyResponse(res *http.Response, body []byte) error {
+	text := string(body)
+	short := strings.TrimSpace(text)
+	if len(short) > maxErrorBodyLen {
+		short = short[:maxErrorBodyLen] + "..."
+	}
+
+	switch {
+	case res.StatusCode == http.StatusNotFound && strings.Contains(text, "before it unlocks"):
+		return NotUnlockedError{}
+
+	// AoC answers requests for inputs without a valid session with a 400 asking
+	// you to log in.
+	case res.StatusCode == http.StatusBadRequest,
+		res.StatusCode == http.StatusUnauthorized,
+		res.StatusCode == http.StatusForbidden,
+		strings.Contains(text, "Please log in"):
+		return SessionError{StatusCode: res.StatusCode}
+
+	case res.StatusCode == http.StatusTooManyRequests:
+		return RateLimitedError{RetryAfter: parseRetryAfter(res.Header.Get("Retry-After"))}
+
+	case res.StatusCode >= 500:
+		return ServerError{StatusCode: res.StatusCode, Body: short}
+	}
+	return StatusError{StatusCode: res.StatusCode, Body: short}
+}
+
+// parseRetryAfter reads a Retry-After header in either of its forms.
+func parseRetryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t)
+	}
+	return 0
+}
@@ -0,0 +1,141 @@
+package aoc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchErrorClasses(t *testing.T) {
+	testTable := []struct {
+		Name string
+
+		Status int
+		Header http.Header
+		Body   string
+
+		Want      error
+		Transient bool
+	}{
+		{
+			Name:   "not unlocked",
+			Status: http.StatusNotFound,
+			Body:   "Please don't repeatedly request this endpoint before it unlocks! The calendar countdown is synchronized with the server time.",
+			Want:   ErrNotUnlocked,
+		},
+		{
+			Name:   "logged out",
+			Status: http.StatusBadRequest,
+			Body:   "Puzzle inputs differ by user.  Please log in to get your puzzle input.",
+			Want:   ErrSessionInvalid,
+		},
+		{
+			Name:      "rate limited",
+			Status:    http.StatusTooManyRequests,
+			Header:    http.Header{"Retry-After": {"0"}},
+			Want:      ErrRateLimited,
+			Transient: true,
+		},
+		{
+			Name:      "server error",
+			Status:    http.StatusBadGateway,
+			Body:      "<html>bad gateway</html>",
+			Want:      ErrServer,
+			Transient: true,
+		},
+	}
+
+	for _, entry := range testTable {
+		entry := entry
+		t.Run(
+			entry.Name,
+			func(t *testing.T) {
+				t.Parallel()
+
+				requests := 0
+				srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					requests++
+					for k, v := range entry.Header {
+						w.Header()[k] = v
+					}
+					w.WriteHeader(entry.Status)
+					_, _ = w.Write([]byte(entry.Body))
+				}))
+				defer srv.Close()
+
+				_, err := testFetcher(t, srv).FetchInput(context.Background(), Puzzle{Year: 2022, Day: 1})
+				assert.ErrorIs(t, err, entry.Want)
+
+				if entry.Transient {
+					assert.Equal(t, DefaultRetryAttempts, requests, "transient errors should be retried")
+				} else {
+					assert.Equal(t, 1, requests, "permanent errors should not be retried")
+				}
+			},
+		)
+	}
+}
+
+func TestFetchRecoversFromTransientErrors(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests < 3 {
+			http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("1000\n2000\n"))
+	}))
+	defer srv.Close()
+
+	input, err := testFetcher(t, srv).FetchInput(context.Background(), Puzzle{Year: 2022, Day: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "1000\n2000\n", input)
+	assert.Equal(t, 3, requests)
+}
+
+func TestFetchNetworkError(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	f := testFetcher(t, srv)
+	srv.Close()
+
+	_, err := f.FetchInput(context.Background(), Puzzle{Year: 2022, Day: 1})
+	assert.ErrorIs(t, err, ErrNetwork)
+
+	var netErr NetworkError
+	assert.True(t, errors.As(err, &netErr))
+}
+
+func TestFetchRespectsContextWhileBackingOff(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	f := testFetcher(t, srv)
+	WithRetries(10, time.Hour)(&f)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := f.FetchInput(ctx, Puzzle{Year: 2022, Day: 1})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestRetryDelay(t *testing.T) {
+	p := retryPolicy{attempts: 10, base: time.Second}
+	for retry := 1; retry <= 10; retry++ {
+		d := p.delay(retry)
+		assert.GreaterOrEqual(t, d, time.Duration(0))
+		assert.LessOrEqual(t, d, maxRetryDelay)
+	}
+	assert.LessOrEqual(t, p.delay(1), time.Second)
+}
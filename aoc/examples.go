@@ -0,0 +1,158 @@
+package aoc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"os"
+	"regexp"
+
+	"go.uber.org/zap"
+)
+
+const puzzlePathPattern = "%d/day/%d"
+
+// An Example is a worked example from a puzzle description: a small input and
+// the answer it should produce for one part.
+type Example struct {
+	Part   int    `json:"part"`
+	Input  string `json:"input"`
+	Answer Answer `json:"answer"`
+}
+
+// FetchPuzzlePage downloads the HTML page describing the day's puzzle. Part two
+// only appears on the page once part one has been solved.
+func (f Fetcher) FetchPuzzlePage(ctx context.Context, p Puzzle) (string, error) {
+	if err := f.checkUnlocked(p); err != nil {
+		return "", err
+	}
+
+	body, err := f.get(ctx, f.url(puzzlePathPattern, p.Year, p.Day))
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch puzzle page: %w", err)
+	}
+	return string(body), nil
+}
+
+// FetchExamples returns the worked examples for the day. They are extracted
+// from the puzzle page and saved as a fixture beside the cached input, which
+// can be edited by hand if the wrong block was picked out. The page is fetched
+// again while the fixture is missing an example for part two, as that part may
+// have been unlocked since.
+func (f Fetcher) FetchExamples(ctx context.Context, p Puzzle) ([]Example, error) {
+	cached, err := f.loadExamples(p)
+	switch {
+	case err != nil:
+		f.log.Warn("failed to load cached examples, fetching from aoc", zap.Error(err))
+	case hasExampleForPart(cached, 2):
+		return cached, nil
+	case cached != nil:
+		f.log.Info("cached examples are missing part two, checking aoc for it")
+	}
+
+	page, err := f.FetchPuzzlePage(ctx, p)
+	if err != nil {
+		if cached != nil {
+			f.log.Warn("failed to refresh examples, using cached version", zap.Error(err))
+			return cached, nil
+		}
+		return nil, err
+	}
+
+	examples := parseExamples(page)
+	if len(examples) == 0 {
+		return nil, fmt.Errorf("no examples found on the puzzle page for %s", p)
+	}
+
+	if err := f.saveExamples(p, examples); err != nil {
+		f.log.Warn("failed to save examples to local folder", zap.Error(err))
+	}
+	return examples, nil
+}
+
+func (f Fetcher) examplesFileName(p Puzzle) string {
+	return f.inputFileName(p) + ".examples.json"
+}
+
+func (f Fetcher) loadExamples(p Puzzle) ([]Example, error) {
+	data, err := os.ReadFile(f.examplesFileName(p))
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to read examples file: %w", err)
+	}
+
+	var examples []Example
+	if err := json.Unmarshal(data, &examples); err != nil {
+		return nil, fmt.Errorf("failed to parse examples file: %w", err)
+	}
+	return examples, nil
+}
+
+func (f Fetcher) saveExamples(p Puzzle, examples []Example) error {
+	data, err := json.MarshalIndent(examples, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode examples: %w", err)
+	}
+
+	if err := writeFileAtomic(f.examplesFileName(p), data); err != nil {
+		return fmt.Errorf("failed to write examples file: %w", err)
+	}
+	return nil
+}
+
+var (
+	preCodePattern = regexp.MustCompile(`(?s)<pre><code>(.*?)</code></pre>`)
+
+	// Answers to the examples are given special emphasis, though the order of
+	// the tags varies.
+	emphasisedAnswerPattern = regexp.MustCompile(`<code><em>([^<]*)</em></code>|<em><code>([^<]*)</code></em>`)
+)
+
+// parseExamples extracts an Example for each part described on the puzzle
+// page. The example input is the first code block of the part (part two reuses
+// part one's if it has none), and its answer is the last emphasised code in
+// the part, which is where AoC puts it.
+func parseExamples(page string) []Example {
+	var (
+		res   []Example
+		input string
+	)
+
+	for i, art := range articles(page) {
+		if i >= 2 {
+			break
+		}
+
+		if block := preCodePattern.FindStringSubmatch(art); block != nil {
+			input = html.UnescapeString(tagPattern.ReplaceAllString(block[1], ""))
+		}
+
+		answers := emphasisedAnswerPattern.FindAllStringSubmatch(art, -1)
+		if input == "" || len(answers) == 0 {
+			continue
+		}
+
+		last := answers[len(answers)-1]
+		answer := last[1]
+		if answer == "" {
+			answer = last[2]
+		}
+
+		res = append(res, Example{Part: i + 1, Input: input, Answer: ParseAnswer(html.UnescapeString(answer))})
+	}
+
+	return res
+}
+
+func hasExampleForPart(examples []Example, part int) bool {
+	for _, e := range examples {
+		if e.Part == part {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package aoc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const day01ExampleInput = "1000\n2000\n3000\n\n4000\n\n5000\n6000\n\n7000\n8000\n9000\n\n10000\n"
+
+func TestParseExamples(t *testing.T) {
+	page, err := os.ReadFile("testdata/day01.html")
+	require.NoError(t, err)
+
+	examples := parseExamples(string(page))
+	require.Len(t, examples, 2)
+
+	assert.Equal(t, 1, examples[0].Part)
+	assert.Equal(t, day01ExampleInput, examples[0].Input)
+	assert.Equal(t, "24000", examples[0].Answer.String())
+
+	assert.Equal(t, 2, examples[1].Part)
+	assert.Equal(t, day01ExampleInput, examples[1].Input, "part two should reuse part one's input")
+	assert.Equal(t, "45000", examples[1].Answer.String())
+}
+
+func TestFetchExamplesCaches(t *testing.T) {
+	page, err := os.ReadFile("testdata/day01.html")
+	require.NoError(t, err)
+
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "/2022/day/1", r.URL.Path)
+		_, _ = w.Write(page)
+	}))
+	defer srv.Close()
+
+	f := testFetcher(t, srv)
+
+	first, err := f.FetchExamples(context.Background(), Puzzle{Year: 2022, Day: 1})
+	require.NoError(t, err)
+
+	second, err := f.FetchExamples(context.Background(), Puzzle{Year: 2022, Day: 1})
+	require.NoError(t, err)
+
+	assert.Equal(t, first, second)
+	assert.Equal(t, 1, requests, "examples with both parts should be served from the fixture")
+	assert.FileExists(t, f.examplesFileName(Puzzle{Year: 2022, Day: 1}))
+}
+
+func TestFetchExamplesRefreshesForPartTwo(t *testing.T) {
+	page, err := os.ReadFile("testdata/day01.html")
+	require.NoError(t, err)
+	partOneOnly := string(page[:strings.Index(string(page), `<article class="day-desc"><h2 id="part2">`)])
+
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			_, _ = w.Write([]byte(partOneOnly))
+			return
+		}
+		_, _ = w.Write(page)
+	}))
+	defer srv.Close()
+
+	f := testFetcher(t, srv)
+
+	examples, err := f.FetchExamples(context.Background(), Puzzle{Year: 2022, Day: 1})
+	require.NoError(t, err)
+	assert.Len(t, examples, 1)
+
+	examples, err = f.FetchExamples(context.Background(), Puzzle{Year: 2022, Day: 1})
+	require.NoError(t, err)
+	assert.Len(t, examples, 2)
+	assert.Equal(t, 2, requests)
+}
@@ -0,0 +1,65 @@
+package aoc
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nightmarlin/aoc2022/fakeaoc"
+)
+
+// TestAgainstFakeAoC runs the Fetcher against a fakeaoc server, as an end to
+// end check that the two agree about how AoC behaves.
+func TestAgainstFakeAoC(t *testing.T) {
+	var (
+		ctx = context.Background()
+		p   = Puzzle{Year: 2022, Day: 1}
+	)
+
+	srv := httptest.NewServer(fakeaoc.New(fakeaoc.Config{
+		Fixtures:       "../fakeaoc/testdata",
+		Sessions:       map[string]string{testSession: "Example User"},
+		AnswerCooldown: time.Minute,
+	}))
+	t.Cleanup(srv.Close)
+
+	f := testFetcher(t, srv)
+
+	info, err := f.CheckSession(ctx, 2022)
+	require.NoError(t, err)
+	assert.Equal(t, SessionInfo{User: "Example User"}, info)
+
+	input, err := f.FetchInput(ctx, p)
+	require.NoError(t, err)
+	assert.Equal(t, day01ExampleInput, input)
+
+	examples, err := f.FetchExamples(ctx, p)
+	require.NoError(t, err)
+	require.Len(t, examples, 1, "only part one should be shown before it's solved")
+	assert.Equal(t, "24000", examples[0].Answer.String())
+
+	res, err := f.Submit(ctx, p, 1, IntAnswer(30000))
+	require.NoError(t, err)
+	assert.Equal(t, VerdictTooHigh, res.Verdict)
+	assert.Equal(t, time.Minute, res.Wait)
+
+	res, err = f.Submit(ctx, p, 1, IntAnswer(24000))
+	require.NoError(t, err)
+	assert.Equal(t, VerdictRateLimited, res.Verdict)
+
+	board, err := f.FetchLeaderboard(ctx, 2022, 101)
+	require.NoError(t, err)
+	assert.Equal(t, 101, board.OwnerID)
+
+	_, err = f.FetchLeaderboard(ctx, 2022, 999)
+	assert.Error(t, err, "leaderboards that can't be seen should fail")
+
+	f, err = NewFetcher(f.log, "", t.TempDir(), WithBaseURL(f.root))
+	require.NoError(t, err)
+	_, err = f.FetchInput(ctx, p)
+	assert.ErrorIs(t, err, ErrNoSession)
+}
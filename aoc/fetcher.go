@@ -4,28 +4,52 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"net/http/cookiejar"
 	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"go.uber.org/zap"
 )
 
 const (
-	RootURL    = "https://adventofcode.com/"
-	URLPattern = "https://adventofcode.com/2022/day/%s/input"
+	// RootURL is where AoC is served from, unless WithBaseURL says otherwise.
+	RootURL = "https://adventofcode.com/"
+
+	inputPathPattern = "%d/day/%d/input"
 )
 
 type Fetcher struct {
 	log         *zap.Logger
 	client      *http.Client
+	root        *url.URL
 	localFolder string
+	store       InputStore
+	ledger      *Ledger
+
+	// hasSession is set when a session cookie was given. Without one, only
+	// what's already saved locally can be used.
+	hasSession bool
+	offline    bool
+
+	userAgent string
+	limiter   *limiter
+	retry     retryPolicy
+	now       func() time.Time
 }
 
-func NewFetcher(log *zap.Logger, sessionCookie string, localFolder string) (Fetcher, error) {
+// NewFetcher creates a Fetcher saving to localFolder. sessionCookie may be
+// empty, in which case anything not already saved locally fails with
+// ErrNoSession rather than being requested.
+func NewFetcher(
+	log *zap.Logger,
+	sessionCookie string,
+	localFolder string,
+	opts ...FetcherOption,
+) (Fetcher, error) {
 	cj, err := cookiejar.New(nil)
 	if err != nil {
 		return Fetcher{}, fmt.Errorf("failed to init cookie-jar: %w", err)
@@ -36,129 +60,250 @@ func NewFetcher(log *zap.Logger, sessionCookie string, localFolder string) (Fetc
 		return Fetcher{}, fmt.Errorf("failed to parse root url: %w", err)
 	}
 
-	cj.SetCookies(
-		aocURL,
-		[]*http.Cookie{{Name: "session", Value: sessionCookie, MaxAge: 300}},
-	)
-
 	localFolder, err = filepath.Abs(localFolder)
 	if err != nil {
 		return Fetcher{}, fmt.Errorf("failed to find absolute path: %w", err)
 	}
 
-	err = os.MkdirAll(localFolder, os.ModePerm)
+	err = os.MkdirAll(localFolder, dirPerm)
 	if err != nil {
 		return Fetcher{}, fmt.Errorf("failed to ensure local folder exists: %w", err)
 	}
 
-	return Fetcher{
-			log: log.
-				Named("input-fetcher").
-				With(zap.String("localFolder", localFolder)),
-			client:      &http.Client{Jar: cj},
-			localFolder: localFolder,
-		},
-		nil
+	ledger, err := OpenLedger(filepath.Join(localFolder, LedgerFileName))
+	if err != nil {
+		return Fetcher{}, fmt.Errorf("failed to open answer ledger: %w", err)
+	}
+
+	f := Fetcher{
+		log: log.
+			Named("input-fetcher").
+			With(zap.String("localFolder", localFolder)),
+		client:      &http.Client{Jar: cj},
+		root:        aocURL,
+		localFolder: localFolder,
+		store:       NewFileStore(log, localFolder),
+		ledger:      ledger,
+		hasSession:  sessionCookie != "",
+		userAgent:   DefaultUserAgent,
+		limiter:     newLimiter(DefaultMinInterval),
+		retry:       retryPolicy{attempts: DefaultRetryAttempts, base: DefaultRetryBase},
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		opt(&f)
+	}
+
+	// The cookie is set once the options are applied, as they may have moved
+	// the root elsewhere.
+	if sessionCookie != "" {
+		cj.SetCookies(
+			f.root,
+			[]*http.Cookie{{Name: "session", Value: sessionCookie}},
+		)
+	}
+	return f, nil
+}
+
+// WithBaseURL sends requests to base rather than RootURL, such as to a local
+// fakeaoc server. Paths are resolved relative to it, so it may include a path
+// prefix.
+func WithBaseURL(base *url.URL) FetcherOption {
+	return func(f *Fetcher) {
+		root := *base
+		if !strings.HasSuffix(root.Path, "/") {
+			root.Path += "/"
+		}
+		f.root = &root
+	}
 }
 
-func (f Fetcher) FetchInput(ctx context.Context, day string) (string, error) {
-	exists, err := f.isInputInLocalFolder(day)
+// FetchInput returns the input for the Puzzle from the InputStore if it has
+// been fetched before, or from AoC otherwise. Cached inputs are checked against
+// the metadata saved with them, and fetched again if they don't match.
+//
+// A lock is held on the Puzzle's input while it is checked and fetched, so
+// that when several processes need the same input only one of them fetches it
+// and the rest load it from the InputStore.
+func (f Fetcher) FetchInput(ctx context.Context, p Puzzle) (string, error) {
+	log := f.log.With(zap.Stringer("puzzle", p))
+
+	lock, err := lockFile(ctx, f.inputLockFileName(p))
+	switch {
+	case ctx.Err() != nil:
+		return "", err
+	case err != nil:
+		log.Warn("failed to lock input, continuing without it", zap.Error(err))
+	default:
+		defer func() {
+			if err := lock.Unlock(); err != nil {
+				log.Warn("failed to unlock input", zap.Error(err))
+			}
+		}()
+	}
+
+	exists, err := f.store.Has(p)
 	if err != nil {
-		f.log.Warn("failed to check if input exists in local folder, fetching from aoc", zap.Error(err))
+		log.Warn("failed to check if input exists in store, fetching from aoc", zap.Error(err))
 
 	} else if exists {
-		f.log.Info("input found in local folder, will load from there")
+		log.Info("input found in store, will load from there")
 
-		input, err := f.fetchInputFromLocalFolder(day)
-		if err != nil {
-			f.log.Warn("failed to load input from local folder, fetching from aoc", zap.Error(err))
-		} else {
+		input, err := f.loadCachedInput(p)
+		if errors.Is(err, ErrNotEncrypted) {
+			// Saved before a passphrase was set, so rather than fetching it again
+			// it's encrypted where it is.
+			log.Info("cached input isn't encrypted, encrypting it")
+			input, err = f.encryptCachedInput(p)
+		}
+		switch {
+		case errors.Is(err, ErrDecrypt):
+			// Fetching again would overwrite the input using the wrong passphrase.
+			return "", err
+		case errors.Is(err, ErrInvalidInput):
+			log.Warn("cached input is invalid, fetching from aoc again", zap.Error(err))
+		case err != nil:
+			log.Warn("failed to load input from store, fetching from aoc", zap.Error(err))
+		default:
 			return input, nil
 		}
 
 	} else {
-		f.log.Info("input not found in local folder, fetching from aoc")
+		log.Info("input not found in store, fetching from aoc")
 	}
 
-	input, err := f.fetchInputFromAOC(ctx, day)
+	input, err := f.fetchInputFromAOC(ctx, p)
 	if err != nil {
 		return "", fmt.Errorf("failed to fetch input from aoc: %w", err)
 	}
+	if err := validateInput(p, input); err != nil {
+		return "", fmt.Errorf("aoc responded with something other than an input: %w", err)
+	}
 
-	f.log.Info("fetched input from aoc")
+	log.Info("fetched input from aoc")
 
-	err = f.saveInputToLocalFolder(day, input)
+	err = f.store.Save(p, input)
+	if err == nil {
+		err = f.saveInputMeta(newInputMeta(p, input, f.now()))
+	}
 	if err != nil {
-		f.log.Warn("failed to save input to local folder", zap.Error(err))
+		log.Warn("failed to save input to store", zap.Error(err))
 	} else {
-		f.log.Info("saved input to local folder, future runs will use ths version")
+		log.Info("saved input to store, future runs will use ths version")
 	}
 
 	return input, nil
 }
 
-func (f Fetcher) fetchInputFromAOC(ctx context.Context, day string) (string, error) {
-	u := fmt.Sprintf(URLPattern, day)
-	f.log.Debug("fetching input", zap.String("url", u))
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+// loadCachedInput loads the input from the store and checks it. Inputs saved
+// before metadata was recorded only need to pass the heuristics, after which
+// their metadata is filled in.
+func (f Fetcher) loadCachedInput(p Puzzle) (string, error) {
+	input, err := f.store.Load(p)
 	if err != nil {
-		return "", fmt.Errorf("failed to create http request: %w", err)
+		return "", err
 	}
 
-	res, err := f.client.Do(req)
+	meta, err := f.checkInput(p, input)
 	if err != nil {
-		return "", fmt.Errorf("failed to perform http request: %w", err)
+		return "", err
 	}
-	defer func() { _ = res.Body.Close() }()
 
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		body = []byte(fmt.Sprintf("<failed to read request body: %s>", err.Error()))
+	if meta == nil {
+		if err := f.saveInputMeta(newInputMeta(p, input, f.now())); err != nil {
+			f.log.Warn("failed to save input metadata", zap.Stringer("puzzle", p), zap.Error(err))
+		}
 	}
+	return input, nil
+}
 
-	if res.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("got status %d, wanted 200. body: %s", res.StatusCode, body)
+// encryptCachedInput encrypts an input saved without encryption, then loads it
+// as normal.
+func (f Fetcher) encryptCachedInput(p Puzzle) (string, error) {
+	es, ok := f.store.(*EncryptedStore)
+	if !ok {
+		return "", fmt.Errorf("%s: %w", p, ErrNotEncrypted)
 	}
-
-	return string(body), nil
+	if err := es.Encrypt(p); err != nil {
+		return "", fmt.Errorf("failed to encrypt input: %w", err)
+	}
+	return f.loadCachedInput(p)
 }
 
-// region filesystem
+// checkInput validates a cached input, returning its metadata if it has any.
+func (f Fetcher) checkInput(p Puzzle, input string) (*InputMeta, error) {
+	if err := validateInput(p, input); err != nil {
+		return nil, err
+	}
 
-func (f Fetcher) inputFileName(day string) string {
-	if len(day) == 1 {
-		day = fmt.Sprintf("0%s", day)
+	meta, err := f.loadInputMeta(p)
+	if err != nil {
+		return nil, err
+	}
+	if meta != nil {
+		if err := meta.check(p, input); err != nil {
+			return meta, err
+		}
 	}
-	return filepath.Join(f.localFolder, day)
+	return meta, nil
 }
 
-func (f Fetcher) isInputInLocalFolder(day string) (bool, error) {
-	_, err := os.Stat(f.inputFileName(day))
-	switch {
-	case errors.Is(err, os.ErrNotExist):
-		return false, nil
-	case err != nil:
-		return false, fmt.Errorf("unable to check if input for day exists: %w", err)
-	default:
-		return true, nil
-	}
+// IsInputCached reports whether the input for the Puzzle has already been saved
+// to the InputStore, so that FetchInput won't need to ask AoC for it.
+func (f Fetcher) IsInputCached(p Puzzle) bool {
+	exists, err := f.store.Has(p)
+	return err == nil && exists
 }
 
-func (f Fetcher) fetchInputFromLocalFolder(day string) (string, error) {
-	input, err := os.ReadFile(f.inputFileName(day))
+func (f Fetcher) fetchInputFromAOC(ctx context.Context, p Puzzle) (string, error) {
+	if err := f.checkUnlocked(p); err != nil {
+		return "", err
+	}
+
+	body, err := f.get(ctx, f.url(inputPathPattern, p.Year, p.Day))
 	if err != nil {
-		return "", fmt.Errorf("failed to read input file for day: %w", err)
+		return "", err
 	}
-	return string(input), nil
+	return string(body), nil
 }
 
-func (f Fetcher) saveInputToLocalFolder(day, input string) error {
-	err := os.WriteFile(f.inputFileName(day), []byte(input), os.ModePerm)
+// get performs a GET request against AoC, returning the body of a successful
+// response. Transient failures are retried.
+func (f Fetcher) get(ctx context.Context, u string) ([]byte, error) {
+	return f.getRetrying(ctx, u, true)
+}
+
+// getOnce is get without any retries, for endpoints that AoC asks to be
+// requested rarely.
+func (f Fetcher) getOnce(ctx context.Context, u string) ([]byte, error) {
+	return f.getRetrying(ctx, u, false)
+}
+
+func (f Fetcher) getRetrying(ctx context.Context, u string, retry bool) ([]byte, error) {
+	f.log.Debug("fetching", zap.String("url", u))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
 	if err != nil {
-		return fmt.Errorf("failed to write input file for day: %w", err)
+		return nil, fmt.Errorf("failed to create http request: %w", err)
 	}
-	return nil
+	return f.send(req, retry)
+}
+
+// url resolves the path built from pattern and args against the AoC root.
+func (f Fetcher) url(pattern string, args ...any) string {
+	return f.root.ResolveReference(&url.URL{Path: fmt.Sprintf(pattern, args...)}).String()
+}
+
+// region filesystem
+
+// inputFileName is where a FileStore in the local folder would keep the input,
+// which other files about the Puzzle are saved beside.
+func (f Fetcher) inputFileName(p Puzzle) string {
+	return filepath.Join(f.localFolder, filepath.FromSlash(p.path()))
+}
+
+func (f Fetcher) inputLockFileName(p Puzzle) string {
+	return f.inputFileName(p) + ".lock"
 }
 
 // endregion
@@ -0,0 +1,91 @@
+package aoc
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// filePerm is the mode of every file saved in the local folder. Inputs are
+	// personal to the account, so only the owner may read them.
+	filePerm os.FileMode = 0o600
+
+	// dirPerm is the mode of every folder created in the local folder.
+	dirPerm os.FileMode = 0o700
+)
+
+// writeFileAtomic writes data to a temporary file beside path, then renames it
+// into place, so that other processes reading path see either the old contents
+// or the new ones, never a partial write. Missing parent folders are created.
+func writeFileAtomic(path string, data []byte) (err error) {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, dirPerm); err != nil {
+		return fmt.Errorf("failed to create folder: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	if err := tmp.Chmod(filePerm); err != nil {
+		return fmt.Errorf("failed to set file mode: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		return fmt.Errorf("failed to sync temporary file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temporary file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to move temporary file into place: %w", err)
+	}
+	return nil
+}
+
+// lockPollInterval is how often a held lock is retried.
+const lockPollInterval = 50 * time.Millisecond
+
+// A fileLock is an advisory lock shared between processes, held on a file
+// beside whatever it protects. How it is taken depends on the platform.
+type fileLock struct {
+	path string
+	file *os.File
+}
+
+// lockFile takes the lock at path, waiting until it is released by whoever
+// holds it or ctx is done.
+func lockFile(ctx context.Context, path string) (*fileLock, error) {
+	if err := os.MkdirAll(filepath.Dir(path), dirPerm); err != nil {
+		return nil, fmt.Errorf("failed to create folder for lock: %w", err)
+	}
+
+	l := &fileLock{path: path}
+	for {
+		ok, err := l.tryLock()
+		if err != nil {
+			return nil, fmt.Errorf("failed to take lock: %w", err)
+		}
+		if ok {
+			return l, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("gave up waiting for lock: %w", ctx.Err())
+		case <-time.After(lockPollInterval):
+		}
+	}
+}
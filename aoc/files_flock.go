@@ -0,0 +1,38 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+
+package aoc
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// tryLock takes an flock on the lock file without blocking. The lock file is
+// left in place once unlocked, as removing it would let two processes lock
+// different files at the same path.
+func (l *fileLock) tryLock() (bool, error) {
+	f, err := os.OpenFile(l.path, os.O_RDWR|os.O_CREATE, filePerm)
+	if err != nil {
+		return false, err
+	}
+
+	err = syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	switch {
+	case errors.Is(err, syscall.EWOULDBLOCK):
+		_ = f.Close()
+		return false, nil
+	case err != nil:
+		_ = f.Close()
+		return false, err
+	}
+
+	l.file = f
+	return true, nil
+}
+
+// Unlock releases the lock.
+func (l *fileLock) Unlock() error {
+	// Closing the file releases the flock.
+	return l.file.Close()
+}
@@ -0,0 +1,38 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package aoc
+
+import (
+	"errors"
+	"os"
+	"time"
+)
+
+// staleLockAge is how old a lock file must be before it is assumed to have been
+// left behind by a process that exited without unlocking.
+const staleLockAge = 10 * time.Minute
+
+// tryLock takes the lock by creating the lock file, which fails if another
+// process already has. There is no flock here, so a lock left behind by a
+// crashed process is only broken once it is older than staleLockAge.
+func (l *fileLock) tryLock() (bool, error) {
+	f, err := os.OpenFile(l.path, os.O_RDWR|os.O_CREATE|os.O_EXCL, filePerm)
+	switch {
+	case errors.Is(err, os.ErrExist):
+		if info, err := os.Stat(l.path); err == nil && time.Since(info.ModTime()) > staleLockAge {
+			_ = os.Remove(l.path)
+		}
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+
+	l.file = f
+	return true, nil
+}
+
+// Unlock releases the lock.
+func (l *fileLock) Unlock() error {
+	_ = l.file.Close()
+	return os.Remove(l.path)
+}
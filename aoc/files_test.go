@@ -0,0 +1,89 @@
+package aoc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "2022", "01")
+
+	require.NoError(t, writeFileAtomic(path, []byte("first\n")))
+	require.NoError(t, writeFileAtomic(path, []byte("second\n")))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "second\n", string(data))
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "no temporary files should be left behind")
+
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, filePerm, info.Mode().Perm())
+
+		info, err = os.Stat(filepath.Dir(path))
+		require.NoError(t, err)
+		assert.Zero(t, info.Mode().Perm()&^dirPerm, "only the owner should have access to the folder")
+	}
+}
+
+func TestLockFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "01.lock")
+
+	lock, err := lockFile(context.Background(), path)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*lockPollInterval)
+	defer cancel()
+	_, err = lockFile(ctx, path)
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "the lock is already held")
+
+	require.NoError(t, lock.Unlock())
+
+	lock, err = lockFile(context.Background(), path)
+	require.NoError(t, err, "the lock should be free once unlocked")
+	require.NoError(t, lock.Unlock())
+}
+
+func TestFetchInputConcurrently(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		time.Sleep(20 * time.Millisecond)
+		_, _ = w.Write([]byte(day01ExampleInput))
+	}))
+	defer srv.Close()
+
+	f := testFetcher(t, srv)
+	p := Puzzle{Year: 2022, Day: 1}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			input, err := f.FetchInput(context.Background(), p)
+			assert.NoError(t, err)
+			assert.Equal(t, day01ExampleInput, input)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests), "only the first to take the lock should fetch the input")
+}
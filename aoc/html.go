@@ -0,0 +1,39 @@
+package aoc
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// AoC pages are simple and stable enough that a few regular expressions are
+// all that's needed to pull out the parts we care about, without bringing in a
+// full HTML parser.
+
+var (
+	articlePattern    = regexp.MustCompile(`(?s)<article[^>]*>(.*?)</article>`)
+	tagPattern        = regexp.MustCompile(`<[^>]*>`)
+	whitespacePattern = regexp.MustCompile(`\s+`)
+)
+
+// articles returns the inner HTML of each <article> element in the page.
+func articles(page string) []string {
+	var res []string
+	for _, m := range articlePattern.FindAllStringSubmatch(page, -1) {
+		res = append(res, m[1])
+	}
+	return res
+}
+
+// textContent strips the tags from a fragment of HTML, unescapes entities and
+// collapses runs of whitespace, leaving the text a browser would display.
+func textContent(fragment string) string {
+	text := html.UnescapeString(tagPattern.ReplaceAllString(fragment, ""))
+	return strings.TrimSpace(whitespacePattern.ReplaceAllString(text, " "))
+}
+
+// textLines strips the tags from a fragment of preformatted HTML, keeping its
+// line breaks.
+func textLines(fragment string) string {
+	return html.UnescapeString(tagPattern.ReplaceAllString(fragment, ""))
+}
@@ -0,0 +1,232 @@
+package aoc
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Every saved input has a sidecar file recording where it came from and what it
+// looked like, so that a cached input that has been truncated, overwritten or
+// filed under the wrong day can be spotted rather than silently used.
+
+// ErrInvalidInput is matched by InvalidInputError.
+var ErrInvalidInput = errors.New("invalid input")
+
+// InvalidInputError is returned when an input, fetched or cached, doesn't look
+// like a puzzle input.
+type InvalidInputError struct {
+	Puzzle Puzzle
+	Reason string
+}
+
+func (e InvalidInputError) Error() string {
+	return fmt.Sprintf("input for %s is invalid: %s", e.Puzzle, e.Reason)
+}
+
+func (e InvalidInputError) Is(target error) bool { return target == ErrInvalidInput }
+
+// InputMeta describes a saved input.
+type InputMeta struct {
+	Year      int       `json:"year"`
+	Day       int       `json:"day"`
+	FetchedAt time.Time `json:"fetchedAt"`
+	SHA256    string    `json:"sha256"`
+	Length    int       `json:"length"`
+}
+
+func newInputMeta(p Puzzle, input string, fetchedAt time.Time) InputMeta {
+	sum := sha256.Sum256([]byte(input))
+	return InputMeta{
+		Year:      p.Year,
+		Day:       p.Day,
+		FetchedAt: fetchedAt.UTC(),
+		SHA256:    hex.EncodeToString(sum[:]),
+		Length:    len(input),
+	}
+}
+
+// Puzzle returns the Puzzle the input belongs to.
+func (m InputMeta) Puzzle() Puzzle { return Puzzle{Year: m.Year, Day: m.Day} }
+
+// check returns an InvalidInputError if the input isn't the one described.
+func (m InputMeta) check(p Puzzle, input string) error {
+	want := newInputMeta(p, input, m.FetchedAt)
+	switch {
+	case m.Puzzle() != p:
+		return InvalidInputError{Puzzle: p, Reason: fmt.Sprintf("its metadata says it belongs to %s", m.Puzzle())}
+	case m.Length != want.Length:
+		return InvalidInputError{Puzzle: p, Reason: fmt.Sprintf("it is %d bytes long, but was %d when fetched", want.Length, m.Length)}
+	case m.SHA256 != want.SHA256:
+		return InvalidInputError{Puzzle: p, Reason: "it has changed since it was fetched"}
+	}
+	return nil
+}
+
+// validateInput applies some heuristics to spot bodies that AoC, or something
+// between us and AoC, sent instead of a puzzle input.
+func validateInput(p Puzzle, input string) error {
+	invalid := func(reason string) error { return InvalidInputError{Puzzle: p, Reason: reason} }
+
+	trimmed := strings.TrimSpace(input)
+	lower := strings.ToLower(trimmed)
+	switch {
+	case trimmed == "":
+		return invalid("it is empty")
+	case strings.Contains(input, "Please log in"), strings.Contains(input, "Puzzle inputs differ by user"):
+		return invalid("it is a request to log in, the session has probably expired")
+	case strings.HasPrefix(lower, "<!doctype"), strings.HasPrefix(lower, "<html"), strings.Contains(lower, "</html>"):
+		return invalid("it is an HTML page")
+	case !strings.HasSuffix(input, "\n"):
+		// AoC always ends inputs with a newline, so one without was cut short.
+		return invalid("it doesn't end with a newline, so was probably truncated")
+	}
+	return nil
+}
+
+var (
+	// ErrNoInputMeta is reported by AuditInputs for inputs saved before their
+	// metadata was recorded.
+	ErrNoInputMeta = errors.New("no metadata recorded for the input")
+
+	// ErrOrphanedInputMeta is reported by AuditInputs for metadata whose input
+	// is missing.
+	ErrOrphanedInputMeta = errors.New("metadata recorded for an input that isn't saved")
+)
+
+// An InputAudit is the result of checking a single cached input.
+type InputAudit struct {
+	Puzzle Puzzle
+	Meta   *InputMeta // Meta is nil if no metadata was found.
+	Err    error      // Err is nil if the input is fine.
+}
+
+// AuditInputs checks every cached input, for each event up to the current
+// year, against its metadata and the heuristics used when fetching.
+func (f Fetcher) AuditInputs() []InputAudit {
+	var res []InputAudit
+	for year := FirstYear; year <= f.now().Year(); year++ {
+		for day := 1; day <= DaysPerYear; day++ {
+			if a, ok := f.auditInput(Puzzle{Year: year, Day: day}); ok {
+				res = append(res, a)
+			}
+		}
+	}
+	return res
+}
+
+// auditInput checks the input for a single Puzzle, returning false if neither
+// it nor its metadata have been saved.
+func (f Fetcher) auditInput(p Puzzle) (InputAudit, bool) {
+	a := InputAudit{Puzzle: p}
+
+	exists, err := f.store.Has(p)
+	if err != nil {
+		a.Err = err
+		return a, true
+	}
+
+	if !exists {
+		a.Meta, a.Err = f.loadInputMeta(p)
+		switch {
+		case a.Err != nil:
+			return a, true
+		case a.Meta != nil:
+			a.Err = ErrOrphanedInputMeta
+			return a, true
+		}
+		return a, false
+	}
+
+	input, err := f.store.Load(p)
+	if err != nil {
+		a.Err = err
+		return a, true
+	}
+
+	a.Meta, a.Err = f.checkInput(p, input)
+	if a.Err == nil && a.Meta == nil {
+		a.Err = ErrNoInputMeta
+	}
+	return a, true
+}
+
+// RepairInput fixes the problem an audit found, where it can. Missing metadata
+// is recorded and unencrypted inputs are encrypted, while invalid inputs and
+// orphaned metadata are deleted so that the input is fetched afresh when next
+// needed.
+func (f Fetcher) RepairInput(a InputAudit) error {
+	switch {
+	case a.Err == nil:
+		return nil
+
+	case errors.Is(a.Err, ErrNotEncrypted):
+		_, err := f.encryptCachedInput(a.Puzzle)
+		return err
+
+	case errors.Is(a.Err, ErrNoInputMeta):
+		input, err := f.store.Load(a.Puzzle)
+		if err != nil {
+			return err
+		}
+		return f.saveInputMeta(newInputMeta(a.Puzzle, input, f.now()))
+
+	case errors.Is(a.Err, ErrInvalidInput), errors.Is(a.Err, ErrOrphanedInputMeta):
+		if err := f.store.Delete(a.Puzzle); err != nil {
+			return err
+		}
+		return f.deleteInputMeta(a.Puzzle)
+	}
+	return fmt.Errorf("unable to repair automatically: %w", a.Err)
+}
+
+// region filesystem
+
+func (f Fetcher) inputMetaFileName(p Puzzle) string {
+	return f.inputFileName(p) + ".meta.json"
+}
+
+// loadInputMeta returns the sidecar for the Puzzle's input, or nil if there
+// isn't one.
+func (f Fetcher) loadInputMeta(p Puzzle) (*InputMeta, error) {
+	contents, err := os.ReadFile(f.inputMetaFileName(p))
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to read input metadata: %w", err)
+	}
+
+	var meta InputMeta
+	if err := json.Unmarshal(contents, &meta); err != nil {
+		return nil, fmt.Errorf("failed to parse input metadata: %w", err)
+	}
+	return &meta, nil
+}
+
+func (f Fetcher) saveInputMeta(meta InputMeta) error {
+	contents, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode input metadata: %w", err)
+	}
+
+	if err := writeFileAtomic(f.inputMetaFileName(meta.Puzzle()), contents); err != nil {
+		return fmt.Errorf("failed to write input metadata: %w", err)
+	}
+	return nil
+}
+
+func (f Fetcher) deleteInputMeta(p Puzzle) error {
+	err := os.Remove(f.inputMetaFileName(p))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete input metadata: %w", err)
+	}
+	return nil
+}
+
+// endregion
@@ -0,0 +1,166 @@
+package aoc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateInput(t *testing.T) {
+	testTable := []struct {
+		Name string
+
+		Input string
+		Valid bool
+	}{
+		{Name: "input", Input: day01ExampleInput, Valid: true},
+		{Name: "empty", Input: ""},
+		{Name: "blank", Input: "\n\n"},
+		{Name: "log in", Input: "Puzzle inputs differ by user.  Please log in to get your puzzle input.\n"},
+		{Name: "html page", Input: "<!DOCTYPE html>\n<html lang=\"en-us\"><body>502 Bad Gateway</body></html>\n"},
+		{Name: "truncated", Input: "1000\n2000\n30"},
+	}
+
+	for _, entry := range testTable {
+		entry := entry
+		t.Run(
+			entry.Name,
+			func(t *testing.T) {
+				t.Parallel()
+
+				err := validateInput(Puzzle{Year: 2022, Day: 1}, entry.Input)
+				if entry.Valid {
+					assert.NoError(t, err)
+				} else {
+					assert.ErrorIs(t, err, ErrInvalidInput)
+				}
+			},
+		)
+	}
+}
+
+func TestFetchInputRejectsInvalidBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("<html><body>Something went wrong</body></html>\n"))
+	}))
+	defer srv.Close()
+
+	f := testFetcher(t, srv)
+	p := Puzzle{Year: 2022, Day: 1}
+
+	_, err := f.FetchInput(context.Background(), p)
+	assert.ErrorIs(t, err, ErrInvalidInput)
+	assert.False(t, f.IsInputCached(p), "invalid inputs should not be saved")
+}
+
+func TestFetchInputReplacesPoisonedCache(t *testing.T) {
+	testTable := []struct {
+		Name string
+
+		// Poison corrupts the cached input, given the Fetcher it was saved by.
+		Poison func(t *testing.T, f Fetcher, p Puzzle)
+	}{
+		{
+			Name: "html saved as input",
+			Poison: func(t *testing.T, f Fetcher, p Puzzle) {
+				require.NoError(t, f.store.Save(p, "<!DOCTYPE html><html></html>\n"))
+				require.NoError(t, f.deleteInputMeta(p))
+			},
+		},
+		{
+			Name: "truncated",
+			Poison: func(t *testing.T, f Fetcher, p Puzzle) {
+				require.NoError(t, f.store.Save(p, day01ExampleInput[:20]+"\n"))
+			},
+		},
+		{
+			Name: "filed under the wrong day",
+			Poison: func(t *testing.T, f Fetcher, p Puzzle) {
+				require.NoError(t, f.saveInputMeta(newInputMeta(Puzzle{Year: 2022, Day: 2}, day01ExampleInput, time.Now())))
+				require.NoError(t, os.Rename(f.inputMetaFileName(Puzzle{Year: 2022, Day: 2}), f.inputMetaFileName(p)))
+			},
+		},
+	}
+
+	for _, entry := range testTable {
+		entry := entry
+		t.Run(
+			entry.Name,
+			func(t *testing.T) {
+				t.Parallel()
+
+				requests := 0
+				srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					requests++
+					_, _ = w.Write([]byte(day01ExampleInput))
+				}))
+				defer srv.Close()
+
+				f := testFetcher(t, srv)
+				p := Puzzle{Year: 2022, Day: 1}
+
+				_, err := f.FetchInput(context.Background(), p)
+				require.NoError(t, err)
+
+				entry.Poison(t, f, p)
+
+				input, err := f.FetchInput(context.Background(), p)
+				require.NoError(t, err)
+				assert.Equal(t, day01ExampleInput, input)
+				assert.Equal(t, 2, requests, "the poisoned input should have been fetched again")
+
+				meta, err := f.loadInputMeta(p)
+				require.NoError(t, err)
+				require.NotNil(t, meta)
+				assert.NoError(t, meta.check(p, input))
+			},
+		)
+	}
+}
+
+func TestAuditInputs(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	f := testFetcher(t, srv)
+
+	var (
+		ok       = Puzzle{Year: 2022, Day: 1}
+		noMeta   = Puzzle{Year: 2022, Day: 2}
+		invalid  = Puzzle{Year: 2022, Day: 3}
+		orphaned = Puzzle{Year: 2021, Day: 4}
+	)
+	require.NoError(t, f.store.Save(ok, day01ExampleInput))
+	require.NoError(t, f.saveInputMeta(newInputMeta(ok, day01ExampleInput, time.Now())))
+	require.NoError(t, f.store.Save(noMeta, day01ExampleInput))
+	require.NoError(t, f.store.Save(invalid, "Please log in to get your puzzle input.\n"))
+	require.NoError(t, f.saveInputMeta(newInputMeta(orphaned, day01ExampleInput, time.Now())))
+
+	audits := f.AuditInputs()
+	require.Len(t, audits, 4)
+
+	byPuzzle := map[Puzzle]InputAudit{}
+	for _, a := range audits {
+		byPuzzle[a.Puzzle] = a
+	}
+	assert.NoError(t, byPuzzle[ok].Err)
+	assert.ErrorIs(t, byPuzzle[noMeta].Err, ErrNoInputMeta)
+	assert.ErrorIs(t, byPuzzle[invalid].Err, ErrInvalidInput)
+	assert.ErrorIs(t, byPuzzle[orphaned].Err, ErrOrphanedInputMeta)
+
+	for _, a := range audits {
+		require.NoError(t, f.RepairInput(a))
+	}
+
+	audits = f.AuditInputs()
+	require.Len(t, audits, 2, "the invalid input and orphaned metadata should have been deleted")
+	for _, a := range audits {
+		assert.NoError(t, a.Err, "%s", a.Puzzle)
+	}
+}
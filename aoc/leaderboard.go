@@ -0,0 +1,276 @@
+package aoc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const leaderboardPathPattern = "%d/leaderboard/private/view/%d.json"
+
+// LeaderboardRefreshInterval is how often a private leaderboard may be fetched.
+// AoC asks that they aren't requested more than once every 15 minutes.
+const LeaderboardRefreshInterval = 15 * time.Minute
+
+// A Leaderboard is a private leaderboard, as served by AoC's JSON API.
+type Leaderboard struct {
+	OwnerID int                          `json:"owner_id"`
+	Event   string                       `json:"event"`
+	Members map[string]LeaderboardMember `json:"members"`
+
+	// FetchedAt is when the Leaderboard was fetched from AoC, which may be up
+	// to LeaderboardRefreshInterval ago.
+	FetchedAt time.Time `json:"-"`
+}
+
+// A LeaderboardMember is a single member's progress on a Leaderboard.
+type LeaderboardMember struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"` // Name is empty for anonymous users.
+	Stars       int    `json:"stars"`
+	LocalScore  int    `json:"local_score"`
+	GlobalScore int    `json:"global_score"`
+	LastStarTS  int64  `json:"last_star_ts"`
+
+	// CompletionDayLevel records when each star was collected, by day then
+	// part.
+	CompletionDayLevel map[int]map[int]StarCompletion `json:"completion_day_level"`
+}
+
+// A StarCompletion records when a star was collected.
+type StarCompletion struct {
+	GetStarTS int64 `json:"get_star_ts"`
+	StarIndex int64 `json:"star_index"`
+}
+
+// DisplayName returns the member's name as AoC would show it.
+func (m LeaderboardMember) DisplayName() string {
+	if m.Name == "" {
+		return fmt.Sprintf("(anonymous user #%d)", m.ID)
+	}
+	return m.Name
+}
+
+// StarAt returns when the member collected the star for the part of the day,
+// and whether they have.
+func (m LeaderboardMember) StarAt(day, part int) (time.Time, bool) {
+	c, ok := m.CompletionDayLevel[day][part]
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(c.GetStarTS, 0), true
+}
+
+// Delta returns how long the member took to solve part two of the day after
+// solving part one, and whether they have solved both.
+func (m LeaderboardMember) Delta(day int) (time.Duration, bool) {
+	one, ok := m.StarAt(day, 1)
+	if !ok {
+		return 0, false
+	}
+	two, ok := m.StarAt(day, 2)
+	if !ok {
+		return 0, false
+	}
+	return two.Sub(one), true
+}
+
+// Standings returns the members in the order AoC ranks them: by local score,
+// then by who reached it first.
+func (l Leaderboard) Standings() []LeaderboardMember {
+	res := make([]LeaderboardMember, 0, len(l.Members))
+	for _, m := range l.Members {
+		res = append(res, m)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		a, b := res[i], res[j]
+		switch {
+		case a.LocalScore != b.LocalScore:
+			return a.LocalScore > b.LocalScore
+		case a.LastStarTS != b.LastStarTS:
+			return a.LastStarTS < b.LastStarTS
+		}
+		return a.ID < b.ID
+	})
+	return res
+}
+
+// LastDay returns the latest day any member has collected a star on, or 0 if
+// nobody has any.
+func (l Leaderboard) LastDay() int {
+	last := 0
+	for _, m := range l.Members {
+		for day := range m.CompletionDayLevel {
+			if day > last {
+				last = day
+			}
+		}
+	}
+	return last
+}
+
+// cachedLeaderboard is how a Leaderboard is saved locally, remembering when it
+// was fetched so that the refresh interval holds across runs.
+type cachedLeaderboard struct {
+	FetchedAt time.Time `json:"fetchedAt"`
+
+	// AttemptedAt is when AoC last answered a request for the leaderboard,
+	// whether or not it was sent. It's zero in files saved before it was
+	// recorded.
+	AttemptedAt time.Time `json:"attemptedAt"`
+
+	// Leaderboard is AoC's response, which is missing if no attempt to fetch the
+	// leaderboard has succeeded yet.
+	Leaderboard json.RawMessage `json:"leaderboard,omitempty"`
+}
+
+// lastAttempt returns when AoC was last asked for the leaderboard.
+func (c cachedLeaderboard) lastAttempt() time.Time {
+	if c.AttemptedAt.After(c.FetchedAt) {
+		return c.AttemptedAt
+	}
+	return c.FetchedAt
+}
+
+// FetchLeaderboard returns the private leaderboard with the given id for the
+// year. It is cached locally, and AoC is only asked for it again once
+// LeaderboardRefreshInterval has passed since it last answered - even if the
+// answer was an error. Failed requests aren't retried, for the same reason.
+func (f Fetcher) FetchLeaderboard(ctx context.Context, year, id int) (Leaderboard, error) {
+	log := f.log.With(zap.Int("year", year), zap.Int("leaderboard", id))
+
+	cache, err := f.loadLeaderboard(year, id)
+	if err != nil {
+		log.Warn("failed to load cached leaderboard, fetching from aoc", zap.Error(err))
+		cache = cachedLeaderboard{}
+	}
+
+	cached, err := parseCachedLeaderboard(cache)
+	if err != nil {
+		log.Warn("failed to parse cached leaderboard, fetching from aoc", zap.Error(err))
+	}
+
+	if next := cache.lastAttempt().Add(LeaderboardRefreshInterval); f.now().Before(next) {
+		if cached == nil {
+			return Leaderboard{}, fmt.Errorf(
+				"leaderboard %d couldn't be fetched at %s, and aoc asks that it isn't requested again until %s",
+				id, cache.lastAttempt().Format(time.RFC3339), next.Format(time.RFC3339),
+			)
+		}
+		log.Info("cached leaderboard is recent enough, not fetching", zap.Time("fetchedAt", cached.FetchedAt))
+		return *cached, nil
+	}
+
+	attemptedAt := f.now()
+	body, err := f.getOnce(ctx, f.url(leaderboardPathPattern, year, id))
+
+	var lb Leaderboard
+	if err == nil {
+		if jsonErr := json.Unmarshal(body, &lb); jsonErr != nil {
+			// AoC redirects to an HTML page when the leaderboard can't be viewed.
+			err = fmt.Errorf(
+				"aoc didn't send leaderboard %d, check the id and that the account is a member: %w",
+				id, jsonErr,
+			)
+		}
+	}
+
+	if err != nil {
+		if answered(err) {
+			cache.AttemptedAt = attemptedAt
+			if err := f.saveLeaderboard(year, id, cache); err != nil {
+				log.Warn("failed to save leaderboard attempt to local folder", zap.Error(err))
+			}
+		}
+
+		if cached != nil {
+			log.Warn("failed to refresh leaderboard, using cached version", zap.Error(err))
+			return *cached, nil
+		}
+		return Leaderboard{}, fmt.Errorf("failed to fetch leaderboard: %w", err)
+	}
+
+	lb.FetchedAt = attemptedAt
+	cache = cachedLeaderboard{FetchedAt: attemptedAt, AttemptedAt: attemptedAt, Leaderboard: body}
+	if err := f.saveLeaderboard(year, id, cache); err != nil {
+		log.Warn("failed to save leaderboard to local folder", zap.Error(err))
+	}
+	return lb, nil
+}
+
+// answered reports whether the failed request got a response from AoC, which
+// holds off the next request. Without one - because nothing was sent, or it was
+// given up on or lost on the way - there's nothing to hold off.
+func answered(err error) bool {
+	switch {
+	case errors.Is(err, ErrOffline),
+		errors.Is(err, ErrNoSession),
+		errors.Is(err, ErrNetwork),
+		errors.Is(err, context.Canceled),
+		errors.Is(err, context.DeadlineExceeded):
+		return false
+	}
+	return true
+}
+
+// parseCachedLeaderboard returns the Leaderboard saved in the cache, or nil if
+// it has none.
+func parseCachedLeaderboard(cache cachedLeaderboard) (*Leaderboard, error) {
+	if len(cache.Leaderboard) == 0 {
+		return nil, nil
+	}
+
+	var lb Leaderboard
+	if err := json.Unmarshal(cache.Leaderboard, &lb); err != nil {
+		return nil, fmt.Errorf("failed to parse leaderboard file: %w", err)
+	}
+	lb.FetchedAt = cache.FetchedAt
+	return &lb, nil
+}
+
+// region filesystem
+
+func (f Fetcher) leaderboardFileName(year, id int) string {
+	return filepath.Join(f.localFolder, strconv.Itoa(year), fmt.Sprintf("leaderboard-%d.json", id))
+}
+
+// loadLeaderboard reads the cache for the leaderboard, which is empty if it
+// has never been requested.
+func (f Fetcher) loadLeaderboard(year, id int) (cachedLeaderboard, error) {
+	data, err := os.ReadFile(f.leaderboardFileName(year, id))
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return cachedLeaderboard{}, nil
+	case err != nil:
+		return cachedLeaderboard{}, fmt.Errorf("failed to read leaderboard file: %w", err)
+	}
+
+	var cache cachedLeaderboard
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return cachedLeaderboard{}, fmt.Errorf("failed to parse leaderboard file: %w", err)
+	}
+	return cache, nil
+}
+
+func (f Fetcher) saveLeaderboard(year, id int, cache cachedLeaderboard) error {
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode leaderboard: %w", err)
+	}
+
+	if err := writeFileAtomic(f.leaderboardFileName(year, id), data); err != nil {
+		return fmt.Errorf("failed to write leaderboard file: %w", err)
+	}
+	return nil
+}
+
+// endregion
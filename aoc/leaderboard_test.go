@@ -0,0 +1,152 @@
+package aoc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLeaderboard(t *testing.T) {
+	data, err := os.ReadFile("testdata/leaderboard.json")
+	require.NoError(t, err)
+
+	var lb Leaderboard
+	require.NoError(t, json.Unmarshal(data, &lb))
+
+	standings := lb.Standings()
+	require.Len(t, standings, 3)
+
+	// The first two are tied on score, so whoever got there first wins.
+	assert.Equal(t, "Example User", standings[0].DisplayName())
+	assert.Equal(t, "Another User", standings[1].DisplayName())
+	assert.Equal(t, "(anonymous user #303)", standings[2].DisplayName())
+
+	assert.Equal(t, 2, lb.LastDay())
+
+	unlock := Puzzle{Year: 2022, Day: 1}.UnlockTime()
+	at, ok := standings[1].StarAt(1, 1)
+	require.True(t, ok)
+	assert.Equal(t, time.Minute, at.Sub(unlock))
+
+	delta, ok := standings[1].Delta(1)
+	require.True(t, ok)
+	assert.Equal(t, 3*time.Minute, delta)
+
+	_, ok = standings[1].Delta(2)
+	assert.False(t, ok, "part two of day 2 hasn't been solved")
+}
+
+func TestFetchLeaderboardRespectsRefreshInterval(t *testing.T) {
+	data, err := os.ReadFile("testdata/leaderboard.json")
+	require.NoError(t, err)
+
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "/2022/leaderboard/private/view/101.json", r.URL.Path)
+		_, _ = w.Write(data)
+	}))
+	defer srv.Close()
+
+	now := time.Date(2022, time.December, 3, 12, 0, 0, 0, time.UTC)
+	f := testFetcher(t, srv)
+	f.now = func() time.Time { return now }
+
+	lb, err := f.FetchLeaderboard(context.Background(), 2022, 101)
+	require.NoError(t, err)
+	assert.Len(t, lb.Members, 3)
+	assert.Equal(t, now, lb.FetchedAt)
+
+	now = now.Add(LeaderboardRefreshInterval - time.Second)
+	lb, err = f.FetchLeaderboard(context.Background(), 2022, 101)
+	require.NoError(t, err)
+	assert.Len(t, lb.Members, 3)
+	assert.Equal(t, 1, requests, "the leaderboard shouldn't be fetched again within the refresh interval")
+
+	now = now.Add(2 * time.Second)
+	lb, err = f.FetchLeaderboard(context.Background(), 2022, 101)
+	require.NoError(t, err)
+	assert.Equal(t, 2, requests)
+	assert.Equal(t, now, lb.FetchedAt)
+}
+
+func TestFetchLeaderboardFailureCountsAsAttempt(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	now := time.Date(2022, time.December, 3, 12, 0, 0, 0, time.UTC)
+	f := testFetcher(t, srv)
+	f.now = func() time.Time { return now }
+
+	_, err := f.FetchLeaderboard(context.Background(), 2022, 101)
+	assert.ErrorIs(t, err, ErrServer)
+	assert.Equal(t, 1, requests, "leaderboard requests shouldn't be retried")
+
+	now = now.Add(LeaderboardRefreshInterval - time.Second)
+	_, err = f.FetchLeaderboard(context.Background(), 2022, 101)
+	assert.ErrorContains(t, err, "isn't requested again until")
+	assert.Equal(t, 1, requests, "a failed attempt should hold off the next for the refresh interval")
+
+	now = now.Add(2 * time.Second)
+	_, err = f.FetchLeaderboard(context.Background(), 2022, 101)
+	assert.ErrorIs(t, err, ErrServer)
+	assert.Equal(t, 2, requests)
+}
+
+func TestFetchLeaderboardUnansweredIsNotAttempt(t *testing.T) {
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	testTable := []struct {
+		Name string
+
+		Ctx     context.Context
+		WantErr error
+	}{
+		{Name: "network error", Ctx: context.Background(), WantErr: ErrNetwork},
+		{Name: "cancelled", Ctx: cancelled, WantErr: context.Canceled},
+	}
+
+	for _, entry := range testTable {
+		entry := entry
+		t.Run(
+			entry.Name,
+			func(t *testing.T) {
+				t.Parallel()
+
+				f := testFetcher(t, closed)
+
+				_, err := f.FetchLeaderboard(entry.Ctx, 2022, 101)
+				assert.ErrorIs(t, err, entry.WantErr)
+
+				cache, err := f.loadLeaderboard(2022, 101)
+				require.NoError(t, err)
+				assert.True(t, cache.AttemptedAt.IsZero(), "a request AoC didn't answer shouldn't hold off the next")
+			},
+		)
+	}
+}
+
+func TestFetchLeaderboardWithoutAccess(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("<!DOCTYPE html><html><body>Private Leaderboard</body></html>"))
+	}))
+	defer srv.Close()
+
+	_, err := testFetcher(t, srv).FetchLeaderboard(context.Background(), 2022, 101)
+	assert.ErrorContains(t, err, "check the id")
+}
@@ -0,0 +1,298 @@
+package aoc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LedgerFileName is the name of the ledger file kept alongside the inputs.
+const LedgerFileName = "ledger.json"
+
+var (
+	// ErrKnownWrong is returned by Ledger.Check for an answer that AoC has
+	// already rejected.
+	ErrKnownWrong = errors.New("answer is already known to be wrong")
+
+	// ErrOutOfBounds is returned by Ledger.Check for a numeric answer that lies
+	// outside the bounds learned from earlier "too high" or "too low" verdicts.
+	ErrOutOfBounds = errors.New("answer is outside the known bounds")
+
+	// ErrAlreadySolved is returned by Ledger.Check when the correct answer has
+	// already been found.
+	ErrAlreadySolved = errors.New("part has already been solved")
+)
+
+// An Attempt is a single answer submitted to AoC.
+type Attempt struct {
+	Answer  Answer    `json:"answer"`
+	Verdict Verdict   `json:"verdict"`
+	At      time.Time `json:"at"`
+}
+
+// A LedgerEntry holds everything known about the answer to one part of a day.
+type LedgerEntry struct {
+	Attempts []Attempt `json:"attempts"`
+
+	// Low is the greatest answer known to be too low, and High the least known
+	// to be too high. The answer must lie strictly between them.
+	Low  *Answer `json:"low,omitempty"`
+	High *Answer `json:"high,omitempty"`
+
+	Correct *Answer `json:"correct,omitempty"`
+}
+
+// A Ledger is a persisted record of every answer submitted to AoC. It is used
+// to refuse submissions that are bound to be rejected, saving the lockout that
+// each wrong answer incurs. A Ledger is safe for concurrent use.
+type Ledger struct {
+	path string
+
+	mu      sync.Mutex
+	puzzles map[string]map[string]*LedgerEntry // puzzle -> part -> entry
+}
+
+// OpenLedger loads the ledger stored at path, or starts an empty one if the
+// file does not exist yet.
+func OpenLedger(path string) (*Ledger, error) {
+	puzzles, err := readLedgerFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Ledger{path: path, puzzles: puzzles}, nil
+}
+
+// readLedgerFile reads the entries saved at path, which are empty if the file
+// does not exist yet.
+func readLedgerFile(path string) (map[string]map[string]*LedgerEntry, error) {
+	puzzles := make(map[string]map[string]*LedgerEntry)
+
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return puzzles, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to read ledger: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &puzzles); err != nil {
+		return nil, fmt.Errorf("failed to parse ledger %q: %w", path, err)
+	}
+
+	// Ledgers written before multiple years were supported are keyed by day
+	// alone, and can only contain 2022's answers.
+	for key, parts := range puzzles {
+		if !strings.Contains(key, "/") {
+			delete(puzzles, key)
+			puzzles[fmt.Sprintf("%d/%s", DefaultYear, key)] = parts
+		}
+	}
+	return puzzles, nil
+}
+
+// Entry returns a copy of what is known about the part of the Puzzle, and
+// whether anything has been recorded for it.
+func (l *Ledger) Entry(p Puzzle, part int) (LedgerEntry, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e := l.entry(p, part, false)
+	if e == nil {
+		return LedgerEntry{}, false
+	}
+
+	cpy := *e
+	cpy.Attempts = append([]Attempt(nil), e.Attempts...)
+	return cpy, true
+}
+
+// Check reports whether submitting the answer is pointless given what is
+// already known, returning an error wrapping ErrKnownWrong, ErrOutOfBounds or
+// ErrAlreadySolved if so.
+//
+// Anything other processes have recorded since the ledger was opened is merged
+// in before checking, so that an answer they've already had rejected isn't
+// sent again.
+func (l *Ledger) Check(p Puzzle, part int, answer Answer) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// The file is always replaced whole, so it can still be read safely if the
+	// lock can't be taken.
+	if lock, err := lockFile(context.Background(), l.ledgerLockFileName()); err == nil {
+		defer func() { _ = lock.Unlock() }()
+	}
+
+	saved, err := readLedgerFile(l.path)
+	if err != nil {
+		return err
+	}
+	l.merge(saved)
+
+	e := l.entry(p, part, false)
+	if e == nil {
+		return nil
+	}
+
+	if e.Correct != nil {
+		if e.Correct.Equal(answer) {
+			return fmt.Errorf("%w: %s was accepted", ErrAlreadySolved, answer)
+		}
+		return fmt.Errorf("%w: the correct answer is %s", ErrKnownWrong, e.Correct)
+	}
+
+	for _, at := range e.Attempts {
+		if at.Verdict.IsWrong() && at.Answer.Equal(answer) {
+			return fmt.Errorf("%w: %s was %s", ErrKnownWrong, answer, at.Verdict)
+		}
+	}
+
+	if e.Low != nil {
+		if c, ok := answer.Cmp(*e.Low); ok && c <= 0 {
+			return fmt.Errorf("%w: %s is too low, it must be more than %s", ErrOutOfBounds, answer, e.Low)
+		}
+	}
+	if e.High != nil {
+		if c, ok := answer.Cmp(*e.High); ok && c >= 0 {
+			return fmt.Errorf("%w: %s is too high, it must be less than %s", ErrOutOfBounds, answer, e.High)
+		}
+	}
+	return nil
+}
+
+// Record adds the outcome of a submission to the ledger, narrowing the bounds
+// where possible, and saves it.
+//
+// Other processes may share the ledger file, so it is locked while the
+// outcome is recorded, and whatever they have saved since it was opened is
+// merged in rather than overwritten.
+func (l *Ledger) Record(p Puzzle, part int, answer Answer, res SubmitResult, at time.Time) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Locks are only held for as long as it takes to write the file, so there's
+	// no need to give up waiting for one.
+	lock, lockErr := lockFile(context.Background(), l.ledgerLockFileName())
+	if lockErr == nil {
+		defer func() { _ = lock.Unlock() }()
+	}
+
+	saved, err := readLedgerFile(l.path)
+	if err != nil {
+		return err
+	}
+	l.merge(saved)
+
+	l.entry(p, part, true).add(Attempt{Answer: answer, Verdict: res.Verdict, At: at})
+
+	if err := l.save(); err != nil {
+		return err
+	}
+	if lockErr != nil {
+		return fmt.Errorf("saved ledger without locking it: %w", lockErr)
+	}
+	return nil
+}
+
+// merge adds the attempts in saved that the Ledger doesn't already have.
+func (l *Ledger) merge(saved map[string]map[string]*LedgerEntry) {
+	for key, parts := range saved {
+		if l.puzzles[key] == nil {
+			l.puzzles[key] = make(map[string]*LedgerEntry)
+		}
+		for part, e := range parts {
+			mine, ok := l.puzzles[key][part]
+			if !ok {
+				l.puzzles[key][part] = e
+				continue
+			}
+			for _, at := range e.Attempts {
+				if !mine.has(at) {
+					mine.add(at)
+				}
+			}
+		}
+	}
+}
+
+// add appends the attempt to the entry, narrowing the bounds where possible.
+func (e *LedgerEntry) add(at Attempt) {
+	e.Attempts = append(e.Attempts, at)
+
+	answer := at.Answer
+	switch at.Verdict {
+	case VerdictCorrect:
+		e.Correct = &answer
+
+	case VerdictTooLow:
+		if !answer.IsNumeric() {
+			break
+		}
+		if e.Low == nil {
+			e.Low = &answer
+		} else if c, _ := answer.Cmp(*e.Low); c > 0 {
+			e.Low = &answer
+		}
+
+	case VerdictTooHigh:
+		if !answer.IsNumeric() {
+			break
+		}
+		if e.High == nil {
+			e.High = &answer
+		} else if c, _ := answer.Cmp(*e.High); c < 0 {
+			e.High = &answer
+		}
+	}
+}
+
+// has reports whether the entry already holds the attempt.
+func (e *LedgerEntry) has(at Attempt) bool {
+	for _, other := range e.Attempts {
+		if other.Verdict == at.Verdict && other.At.Equal(at.At) && other.Answer.Equal(at.Answer) {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *Ledger) entry(p Puzzle, part int, create bool) *LedgerEntry {
+	parts, ok := l.puzzles[p.String()]
+	if !ok {
+		if !create {
+			return nil
+		}
+		parts = make(map[string]*LedgerEntry)
+		l.puzzles[p.String()] = parts
+	}
+
+	key := strconv.Itoa(part)
+	e, ok := parts[key]
+	if !ok && create {
+		e = &LedgerEntry{}
+		parts[key] = e
+	}
+	return e
+}
+
+func (l *Ledger) ledgerLockFileName() string {
+	return l.path + ".lock"
+}
+
+func (l *Ledger) save() error {
+	data, err := json.MarshalIndent(l.puzzles, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode ledger: %w", err)
+	}
+
+	if err := writeFileAtomic(l.path, data); err != nil {
+		return fmt.Errorf("failed to write ledger: %w", err)
+	}
+	return nil
+}
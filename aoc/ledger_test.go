@@ -0,0 +1,142 @@
+package aoc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLedgerCheck(t *testing.T) {
+	l, err := OpenLedger(filepath.Join(t.TempDir(), LedgerFileName))
+	require.NoError(t, err)
+
+	now := time.Now()
+	require.NoError(t, l.Record(Puzzle{Year: 2022, Day: 1}, 1, IntAnswer(100), SubmitResult{Verdict: VerdictTooLow}, now))
+	require.NoError(t, l.Record(Puzzle{Year: 2022, Day: 1}, 1, IntAnswer(200), SubmitResult{Verdict: VerdictTooHigh}, now))
+	require.NoError(t, l.Record(Puzzle{Year: 2022, Day: 1}, 1, IntAnswer(150), SubmitResult{Verdict: VerdictWrong}, now))
+	require.NoError(t, l.Record(Puzzle{Year: 2022, Day: 1}, 1, IntAnswer(50), SubmitResult{Verdict: VerdictTooLow}, now))
+	require.NoError(t, l.Record(Puzzle{Year: 2022, Day: 5}, 1, StringAnswer("CMZ"), SubmitResult{Verdict: VerdictWrong}, now))
+	require.NoError(t, l.Record(Puzzle{Year: 2022, Day: 5}, 2, StringAnswer("MCD"), SubmitResult{Verdict: VerdictCorrect}, now))
+
+	testTable := []struct {
+		Name string
+
+		Day    int
+		Part   int
+		Answer Answer
+		Want   error
+	}{
+		{Name: "unknown day", Day: 2, Part: 1, Answer: IntAnswer(1)},
+		{Name: "within bounds", Day: 1, Part: 1, Answer: IntAnswer(120)},
+		{Name: "known wrong", Day: 1, Part: 1, Answer: IntAnswer(150), Want: ErrKnownWrong},
+		{Name: "below lower bound", Day: 1, Part: 1, Answer: IntAnswer(75), Want: ErrOutOfBounds},
+		{Name: "above upper bound", Day: 1, Part: 1, Answer: IntAnswer(1000), Want: ErrOutOfBounds},
+		{Name: "text known wrong", Day: 5, Part: 1, Answer: StringAnswer("CMZ"), Want: ErrKnownWrong},
+		{Name: "text untried", Day: 5, Part: 1, Answer: StringAnswer("ZMC")},
+		{Name: "already solved", Day: 5, Part: 2, Answer: StringAnswer("MCD"), Want: ErrAlreadySolved},
+		{Name: "other than solution", Day: 5, Part: 2, Answer: StringAnswer("DCM"), Want: ErrKnownWrong},
+	}
+
+	for _, entry := range testTable {
+		entry := entry
+		t.Run(
+			entry.Name,
+			func(t *testing.T) {
+				t.Parallel()
+
+				err := l.Check(Puzzle{Year: 2022, Day: entry.Day}, entry.Part, entry.Answer)
+				if entry.Want == nil {
+					assert.NoError(t, err)
+				} else {
+					assert.ErrorIs(t, err, entry.Want)
+				}
+			},
+		)
+	}
+}
+
+func TestLedgerPersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), LedgerFileName)
+
+	l, err := OpenLedger(path)
+	require.NoError(t, err)
+	require.NoError(t, l.Record(Puzzle{Year: 2022, Day: 3}, 2, IntAnswer(70), SubmitResult{Verdict: VerdictTooHigh}, time.Now()))
+
+	reopened, err := OpenLedger(path)
+	require.NoError(t, err)
+
+	e, ok := reopened.Entry(Puzzle{Year: 2022, Day: 3}, 2)
+	require.True(t, ok)
+	require.Len(t, e.Attempts, 1)
+	assert.Equal(t, VerdictTooHigh, e.Attempts[0].Verdict)
+	assert.True(t, IntAnswer(70).Equal(*e.High))
+}
+
+func TestLedgerSharedBetweenProcesses(t *testing.T) {
+	var (
+		path = filepath.Join(t.TempDir(), LedgerFileName)
+		p    = Puzzle{Year: 2022, Day: 1}
+		now  = time.Now()
+	)
+
+	// Each Ledger stands in for a separate process with the ledger open.
+	first, err := OpenLedger(path)
+	require.NoError(t, err)
+	second, err := OpenLedger(path)
+	require.NoError(t, err)
+
+	require.NoError(t, first.Record(p, 1, IntAnswer(200), SubmitResult{Verdict: VerdictTooHigh}, now))
+	assert.ErrorIs(t, second.Check(p, 1, IntAnswer(200)), ErrKnownWrong, "should check what the other has recorded")
+
+	require.NoError(t, second.Record(p, 1, IntAnswer(100), SubmitResult{Verdict: VerdictTooLow}, now))
+
+	assert.ErrorIs(t, second.Check(p, 1, IntAnswer(300)), ErrOutOfBounds, "should know what the other recorded")
+
+	reopened, err := OpenLedger(path)
+	require.NoError(t, err)
+
+	e, ok := reopened.Entry(p, 1)
+	require.True(t, ok)
+	assert.Len(t, e.Attempts, 2, "neither verdict should be lost")
+	assert.True(t, IntAnswer(100).Equal(*e.Low))
+	assert.True(t, IntAnswer(200).Equal(*e.High))
+}
+
+func TestLedgerMigratesDayOnlyKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), LedgerFileName)
+	require.NoError(t, os.WriteFile(path, []byte(`{"4": {"1": {"attempts": [], "correct": "2"}}}`), 0o600))
+
+	l, err := OpenLedger(path)
+	require.NoError(t, err)
+
+	e, ok := l.Entry(Puzzle{Year: 2022, Day: 4}, 1)
+	require.True(t, ok)
+	assert.True(t, IntAnswer(2).Equal(*e.Correct))
+}
+
+func TestSubmitRefusesKnownWrong(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = fmt.Fprint(w, answerPage("That's not the right answer; your answer is too low."))
+	}))
+	defer srv.Close()
+
+	f := testFetcher(t, srv)
+
+	res, err := f.Submit(context.Background(), Puzzle{Year: 2022, Day: 1}, 1, IntAnswer(10))
+	require.NoError(t, err)
+	assert.Equal(t, VerdictTooLow, res.Verdict)
+
+	_, err = f.Submit(context.Background(), Puzzle{Year: 2022, Day: 1}, 1, IntAnswer(5))
+	assert.ErrorIs(t, err, ErrOutOfBounds)
+	assert.Equal(t, 1, requests, "the second answer should not have been sent")
+}
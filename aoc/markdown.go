@@ -0,0 +1,179 @@
+package aoc
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Puzzle descriptions only use a handful of tags, so rather than a general
+// HTML to Markdown converter this handles just those, and drops any others
+// while keeping their text.
+
+// markdownWidth is the column paragraphs are wrapped at, to keep them readable
+// in a terminal.
+const markdownWidth = 80
+
+var (
+	markupPattern = regexp.MustCompile(`<(/?)([a-zA-Z0-9]+)([^>]*)>`)
+	hrefPattern   = regexp.MustCompile(`href="([^"]*)"`)
+
+	// AoC highlights some values as <code><em>, but Markdown can't emphasise
+	// inside a code span, so they are turned inside out first.
+	codeEmPattern = regexp.MustCompile(`(?s)<code><em>(.*?)</em></code>`)
+)
+
+// renderMarkdown converts a fragment of a puzzle page to Markdown. Relative
+// links are resolved against root.
+func renderMarkdown(fragment string, root *url.URL) string {
+	fragment = codeEmPattern.ReplaceAllString(fragment, "<em><code>$1</code></em>")
+
+	r := markdownRenderer{root: root}
+	pos := 0
+	for _, m := range markupPattern.FindAllStringSubmatchIndex(fragment, -1) {
+		r.text(fragment[pos:m[0]])
+		r.tag(strings.ToLower(fragment[m[4]:m[5]]), fragment[m[6]:m[7]], m[3] > m[2])
+		pos = m[1]
+	}
+	r.text(fragment[pos:])
+	r.endBlock()
+
+	return strings.TrimSpace(r.out.String()) + "\n"
+}
+
+type markdownRenderer struct {
+	root *url.URL
+
+	out   strings.Builder // out holds the finished blocks.
+	block strings.Builder // block holds the text of the one being built.
+
+	pre   bool
+	code  int
+	links []string
+}
+
+func (r *markdownRenderer) text(s string) {
+	s = html.UnescapeString(s)
+	if !r.pre {
+		s = whitespacePattern.ReplaceAllString(s, " ")
+	}
+	r.block.WriteString(s)
+}
+
+func (r *markdownRenderer) tag(name, attrs string, closing bool) {
+	if r.pre && name != "pre" {
+		return
+	}
+
+	switch name {
+	case "h1", "h2", "h3":
+		r.endBlock()
+		if !closing {
+			r.block.WriteString(strings.Repeat("#", int(name[1]-'0')) + " ")
+		}
+
+	case "p", "ul", "ol", "article":
+		r.endBlock()
+
+	case "li":
+		r.endLine()
+		if !closing {
+			r.block.WriteString("- ")
+		}
+
+	case "pre":
+		if closing {
+			r.out.WriteString("```\n" + strings.TrimRight(r.block.String(), "\n") + "\n```\n\n")
+			r.block.Reset()
+		} else {
+			r.endBlock()
+		}
+		r.pre = !closing
+
+	case "code":
+		if closing {
+			r.code--
+		} else {
+			r.code++
+		}
+		if (closing && r.code == 0) || (!closing && r.code == 1) {
+			r.block.WriteString("`")
+		}
+
+	case "em", "strong":
+		if r.code == 0 {
+			r.block.WriteString("*")
+		}
+
+	case "a":
+		if !closing {
+			r.links = append(r.links, r.resolve(attrs))
+			r.block.WriteString("[")
+		} else if len(r.links) > 0 {
+			r.block.WriteString("](" + r.links[len(r.links)-1] + ")")
+			r.links = r.links[:len(r.links)-1]
+		}
+
+	case "br":
+		r.endLine()
+	}
+}
+
+// resolve returns the absolute URL of the href in a link's attributes.
+func (r *markdownRenderer) resolve(attrs string) string {
+	m := hrefPattern.FindStringSubmatch(attrs)
+	if m == nil {
+		return ""
+	}
+
+	href := html.UnescapeString(m[1])
+	u, err := url.Parse(href)
+	if err != nil || r.root == nil {
+		return href
+	}
+	return r.root.ResolveReference(u).String()
+}
+
+// endLine finishes the current line of a block, such as a list item.
+func (r *markdownRenderer) endLine() {
+	if text := strings.TrimSpace(r.block.String()); text != "" {
+		r.out.WriteString(wrap(text, markdownWidth, "  ") + "\n")
+	}
+	r.block.Reset()
+}
+
+// endBlock finishes the current block, leaving a blank line after it.
+func (r *markdownRenderer) endBlock() {
+	if text := strings.TrimSpace(r.block.String()); text != "" {
+		r.out.WriteString(wrap(text, markdownWidth, "") + "\n")
+	}
+	r.block.Reset()
+
+	if s := r.out.String(); s != "" && !strings.HasSuffix(s, "\n\n") {
+		r.out.WriteString("\n")
+	}
+}
+
+// wrap breaks text into lines of at most width columns where it can, indenting
+// every line after the first.
+func wrap(text string, width int, indent string) string {
+	var (
+		sb  strings.Builder
+		col int
+	)
+	for i, word := range strings.Fields(text) {
+		switch {
+		case i == 0:
+		case col+1+len(word) > width:
+			sb.WriteString("\n" + indent)
+			col = len(indent)
+		default:
+			sb.WriteString(" ")
+			col++
+		}
+		sb.WriteString(word)
+		col += len(word)
+	}
+	return sb.String()
+}
@@ -0,0 +1,27 @@
+package aoc
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// WithOffline stops the Fetcher from ever making a request to AoC, so that
+// solutions can be run without a network connection. Anything that isn't
+// already saved in the local folder fails with an OfflineError, while cached
+// leaderboards and stats are used however old they are.
+func WithOffline() FetcherOption {
+	return func(f *Fetcher) { f.offline = true }
+}
+
+// checkCanSend returns why a request to u mustn't be made, if it mustn't. It
+// is checked before every request, so nothing reaches the network when
+// offline.
+func (f Fetcher) checkCanSend(u *url.URL) error {
+	switch {
+	case f.offline:
+		return OfflineError{URL: u.String()}
+	case !f.hasSession:
+		return fmt.Errorf("%w, one is needed to request %s", ErrNoSession, u)
+	}
+	return nil
+}
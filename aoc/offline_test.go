@@ -0,0 +1,59 @@
+package aoc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOffline(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("offline fetcher made a request to %s", r.URL)
+	}))
+	defer srv.Close()
+
+	f := testFetcher(t, srv)
+	WithOffline()(&f)
+
+	cached, missing := Puzzle{Year: 2022, Day: 1}, Puzzle{Year: 2022, Day: 2}
+	require.NoError(t, f.store.Save(cached, day01ExampleInput))
+
+	input, err := f.FetchInput(context.Background(), cached)
+	require.NoError(t, err)
+	assert.Equal(t, day01ExampleInput, input)
+
+	_, err = f.FetchInput(context.Background(), missing)
+	assert.ErrorIs(t, err, ErrOffline)
+
+	var offline OfflineError
+	require.ErrorAs(t, err, &offline)
+	assert.Equal(t, srv.URL+"/2022/day/2/input", offline.URL)
+
+	_, err = f.CheckSession(context.Background(), 2022)
+	assert.ErrorIs(t, err, ErrOffline)
+}
+
+func TestWithoutSession(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer srv.Close()
+
+	f := testFetcher(t, srv)
+	f.hasSession = false
+
+	cached := Puzzle{Year: 2022, Day: 1}
+	require.NoError(t, f.store.Save(cached, day01ExampleInput))
+
+	_, err := f.FetchInput(context.Background(), cached)
+	assert.NoError(t, err, "cached inputs don't need a session")
+
+	_, err = f.FetchInput(context.Background(), Puzzle{Year: 2022, Day: 2})
+	assert.ErrorIs(t, err, ErrNoSession)
+	assert.Zero(t, requests)
+}
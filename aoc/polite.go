@@ -0,0 +1,99 @@
+package aoc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// AoC asks that automated tools identify themselves, and don't hit the site
+// more often than they need to.
+
+const (
+	// DefaultUserAgent identifies requests as coming from this tool. AoC asks
+	// that it includes a way to contact you, which WithUserAgent allows.
+	DefaultUserAgent = "github.com/nightmarlin/aoc2022"
+
+	// DefaultMinInterval is the least time left between two requests to AoC.
+	DefaultMinInterval = 3 * time.Second
+)
+
+// unlockZone is the timezone puzzles unlock in. AoC uses US Eastern time, and
+// December is always outside daylight saving, so a fixed offset will do.
+var unlockZone = time.FixedZone("EST", -5*60*60)
+
+// UnlockTime returns when the Puzzle becomes available: midnight US Eastern on
+// its day of December.
+func (p Puzzle) UnlockTime() time.Time {
+	return time.Date(p.Year, time.December, p.Day, 0, 0, 0, 0, unlockZone)
+}
+
+// A FetcherOption configures optional behaviour of a Fetcher.
+type FetcherOption func(*Fetcher)
+
+// WithUserAgent sets the User-Agent sent with every request. It should identify
+// you, such as by including an email address.
+func WithUserAgent(ua string) FetcherOption {
+	return func(f *Fetcher) { f.userAgent = ua }
+}
+
+// WithMinInterval sets the least time left between two requests to AoC.
+func WithMinInterval(d time.Duration) FetcherOption {
+	return func(f *Fetcher) { f.limiter = newLimiter(d) }
+}
+
+// checkUnlocked returns a NotUnlockedError if the Puzzle isn't out yet.
+func (f Fetcher) checkUnlocked(p Puzzle) error {
+	now := f.now()
+	if unlock := p.UnlockTime(); now.Before(unlock) {
+		return NotUnlockedError{Puzzle: p, UnlocksAt: unlock, Remaining: unlock.Sub(now)}
+	}
+	return nil
+}
+
+// WaitForUnlock blocks until the Puzzle is available, or the context is done.
+// It returns immediately for puzzles that are already out.
+func (f Fetcher) WaitForUnlock(ctx context.Context, p Puzzle) error {
+	remaining := p.UnlockTime().Sub(f.now())
+	if remaining <= 0 {
+		return nil
+	}
+
+	f.log.Info(
+		"waiting for puzzle to unlock",
+		zap.Stringer("puzzle", p),
+		zap.Duration("remaining", remaining.Round(time.Second)),
+	)
+
+	// The site is under heavy load at unlock time, so give it a moment.
+	return sleep(ctx, remaining+2*time.Second)
+}
+
+// A limiter spaces out calls to Wait by at least its interval. It is safe for
+// concurrent use, and shared between copies of a Fetcher.
+type limiter struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+func newLimiter(interval time.Duration) *limiter {
+	return &limiter{interval: interval}
+}
+
+// Wait blocks until the next call is allowed, or the context is done.
+func (l *limiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	slot := l.next
+	if slot.Before(now) {
+		slot = now
+	}
+	l.next = slot.Add(l.interval)
+	l.mu.Unlock()
+
+	return sleep(ctx, time.Until(slot))
+}
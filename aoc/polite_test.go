@@ -0,0 +1,73 @@
+package aoc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnlockTime(t *testing.T) {
+	unlock := Puzzle{Year: 2022, Day: 5}.UnlockTime()
+	assert.Equal(t, time.Date(2022, time.December, 5, 5, 0, 0, 0, time.UTC), unlock.UTC())
+}
+
+func TestFetchBeforeUnlock(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer srv.Close()
+
+	p := Puzzle{Year: 2022, Day: 5}
+
+	f := testFetcher(t, srv)
+	f.now = func() time.Time { return p.UnlockTime().Add(-3*time.Hour - 12*time.Minute) }
+
+	_, err := f.FetchInput(context.Background(), p)
+
+	var notUnlocked NotUnlockedError
+	require.True(t, errors.As(err, &notUnlocked), "got %v", err)
+	assert.Equal(t, 3*time.Hour+12*time.Minute, notUnlocked.Remaining)
+	assert.Contains(t, err.Error(), "unlocks in 3h12m0s")
+	assert.Zero(t, requests, "no request should be made for a locked puzzle")
+}
+
+func TestUserAgent(t *testing.T) {
+	var ua string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ua = r.UserAgent()
+		_, _ = w.Write([]byte(day01ExampleInput))
+	}))
+	defer srv.Close()
+
+	f := testFetcher(t, srv)
+	_, err := f.FetchInput(context.Background(), Puzzle{Year: 2022, Day: 1})
+	require.NoError(t, err)
+	assert.Equal(t, DefaultUserAgent, ua)
+
+	WithUserAgent("me@example.com")(&f)
+	_, err = f.FetchPuzzlePage(context.Background(), Puzzle{Year: 2022, Day: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "me@example.com", ua)
+}
+
+func TestLimiter(t *testing.T) {
+	l := newLimiter(20 * time.Millisecond)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		require.NoError(t, l.Wait(ctx))
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.ErrorIs(t, l.Wait(cancelled), context.Canceled)
+}
@@ -0,0 +1,81 @@
+package aoc
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultYear is the event assumed when no year is given.
+	DefaultYear = 2022
+
+	// FirstYear is the year of the first AoC event.
+	FirstYear = 2015
+
+	// DaysPerYear is the number of puzzles in each event.
+	DaysPerYear = 25
+)
+
+// A Puzzle identifies a single day of a single AoC event.
+type Puzzle struct {
+	Year int
+	Day  int
+}
+
+// ParsePuzzle parses a puzzle written as "<day>" or "<year>/<day>", using
+// defaultYear for the former.
+func ParsePuzzle(s string, defaultYear int) (Puzzle, error) {
+	s = strings.TrimSpace(s)
+
+	p := Puzzle{Year: defaultYear}
+	dayStr := s
+	if yearStr, d, ok := strings.Cut(s, "/"); ok {
+		y, err := strconv.Atoi(yearStr)
+		if err != nil {
+			return Puzzle{}, fmt.Errorf("invalid year in %q", s)
+		}
+		p.Year, dayStr = y, d
+	}
+
+	d, err := strconv.Atoi(dayStr)
+	if err != nil {
+		return Puzzle{}, fmt.Errorf("invalid day in %q", s)
+	}
+	p.Day = d
+
+	if err := p.Validate(); err != nil {
+		return Puzzle{}, err
+	}
+	return p, nil
+}
+
+// Validate checks that the Puzzle could exist. AoC started in 2015, and each
+// event has 25 days.
+func (p Puzzle) Validate() error {
+	if p.Year < FirstYear {
+		return fmt.Errorf("year must be %d or later, got %d", FirstYear, p.Year)
+	}
+	if p.Day < 1 || p.Day > DaysPerYear {
+		return fmt.Errorf("day must be a number between 1 and %d, got %d", DaysPerYear, p.Day)
+	}
+	return nil
+}
+
+// Less orders Puzzles chronologically.
+func (p Puzzle) Less(o Puzzle) bool {
+	if p.Year != o.Year {
+		return p.Year < o.Year
+	}
+	return p.Day < o.Day
+}
+
+// String formats the Puzzle as "<year>/<day>", which ParsePuzzle accepts.
+func (p Puzzle) String() string {
+	return fmt.Sprintf("%d/%d", p.Year, p.Day)
+}
+
+// path returns the location of the Puzzle's files relative to the cache root.
+func (p Puzzle) path() string {
+	return fmt.Sprintf("%d/%02d", p.Year, p.Day)
+}
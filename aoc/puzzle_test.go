@@ -0,0 +1,44 @@
+package aoc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePuzzle(t *testing.T) {
+	testTable := []struct {
+		Name string
+
+		Input   string
+		Want    Puzzle
+		WantErr bool
+	}{
+		{Name: "day only", Input: "4", Want: Puzzle{Year: 2022, Day: 4}},
+		{Name: "padded day", Input: "04", Want: Puzzle{Year: 2022, Day: 4}},
+		{Name: "year and day", Input: "2021/25", Want: Puzzle{Year: 2021, Day: 25}},
+		{Name: "day too large", Input: "26", WantErr: true},
+		{Name: "day too small", Input: "2022/0", WantErr: true},
+		{Name: "before aoc", Input: "2014/1", WantErr: true},
+		{Name: "not a number", Input: "banana", WantErr: true},
+	}
+
+	for _, entry := range testTable {
+		entry := entry
+		t.Run(
+			entry.Name,
+			func(t *testing.T) {
+				t.Parallel()
+
+				got, err := ParsePuzzle(entry.Input, DefaultYear)
+				if entry.WantErr {
+					assert.Error(t, err)
+					return
+				}
+
+				assert.NoError(t, err)
+				assert.Equal(t, entry.Want, got)
+			},
+		)
+	}
+}
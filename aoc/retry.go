@@ -0,0 +1,171 @@
+package aoc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultRetryAttempts is how many times an idempotent request is tried
+	// before giving up on a transient failure.
+	DefaultRetryAttempts = 4
+
+	// DefaultRetryBase is the delay before the first retry. Each subsequent
+	// delay doubles, up to maxRetryDelay.
+	DefaultRetryBase = time.Second
+
+	maxRetryDelay = time.Minute
+)
+
+// WithRetries sets how many attempts are made at requests that fail
+// transiently, and the delay before the first retry. attempts of 1 disables
+// retrying.
+func WithRetries(attempts int, base time.Duration) FetcherOption {
+	return func(f *Fetcher) {
+		if attempts < 1 {
+			attempts = 1
+		}
+		f.retry = retryPolicy{attempts: attempts, base: base}
+	}
+}
+
+type retryPolicy struct {
+	attempts int
+	base     time.Duration
+}
+
+// delay returns how long to wait before the given retry (starting from 1),
+// using exponential backoff with full jitter so that parallel clients don't
+// retry in lockstep.
+func (p retryPolicy) delay(retry int) time.Duration {
+	if p.base <= 0 {
+		return 0
+	}
+
+	ceiling := p.base << (retry - 1)
+	if ceiling <= 0 || ceiling > maxRetryDelay {
+		ceiling = maxRetryDelay
+	}
+	return time.Duration(jitter.Int63n(int64(ceiling) + 1))
+}
+
+// jitter is seeded explicitly, as the global source isn't in this module's Go
+// version.
+var jitter = &lockedRand{r: rand.New(rand.NewSource(time.Now().UnixNano()))}
+
+type lockedRand struct {
+	mu sync.Mutex
+	r  *rand.Rand
+}
+
+func (l *lockedRand) Int63n(n int64) int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.r.Int63n(n)
+}
+
+// send performs the request against AoC and returns the body of a successful
+// response, or one of the typed errors otherwise. When retry is set, transient
+// failures are retried according to the Fetcher's retry policy; it should only
+// be set for idempotent requests.
+//
+// Every attempt waits for the rate limiter and carries the User-Agent.
+func (f Fetcher) send(req *http.Request, retry bool) ([]byte, error) {
+	if err := f.checkCanSend(req.URL); err != nil {
+		return nil, err
+	}
+	ctx := req.Context()
+
+	attempts := 1
+	if retry {
+		attempts = f.retry.attempts
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		var body []byte
+		body, err = f.sendOnce(req)
+		if err == nil || !isTransient(err) || attempt >= attempts {
+			return body, err
+		}
+
+		wait := f.retry.delay(attempt)
+		var rl RateLimitedError
+		if errors.As(err, &rl) && rl.RetryAfter > wait {
+			wait = rl.RetryAfter
+		}
+
+		f.log.Warn(
+			"request failed, retrying",
+			zap.String("url", req.URL.String()),
+			zap.Int("attempt", attempt),
+			zap.Duration("wait", wait),
+			zap.Error(err),
+		)
+
+		if err := sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (f Fetcher) sendOnce(orig *http.Request) ([]byte, error) {
+	ctx := orig.Context()
+	if err := f.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+
+	req := orig.Clone(ctx)
+	if orig.GetBody != nil {
+		body, err := orig.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("failed to rewind request body: %w", err)
+		}
+		req.Body = body
+	}
+	req.Header.Set("User-Agent", f.userAgent)
+
+	res, err := f.client.Do(req)
+	if err != nil {
+		var permanent PermanentError
+		switch {
+		case ctx.Err() != nil:
+			return nil, ctx.Err()
+		case errors.As(err, &permanent):
+			return nil, err
+		}
+		return nil, NetworkError{Err: err}
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, NetworkError{Err: fmt.Errorf("failed to read response body: %w", err)}
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, classifyResponse(res, body)
+	}
+	return body, nil
+}
+
+// sleep waits for d, or until the context is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,93 @@
+package aoc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// The session cookie grants full access to an AoC account, so when it is kept
+// in a file that file must only be readable by its owner.
+
+// ErrSessionFileInsecure is returned by ReadSessionFile when other users could
+// read the file.
+var ErrSessionFileInsecure = errors.New("session file is readable by other users")
+
+// ReadSessionFile reads a session cookie saved in a file. The cookie can be
+// given by itself or as "session=<cookie>", as copied from a browser, and
+// surrounding whitespace is ignored.
+func ReadSessionFile(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read session file: %w", err)
+	}
+
+	// Windows doesn't have unix permissions, and reports every file as 0666.
+	if perm := info.Mode().Perm(); runtime.GOOS != "windows" && perm&0o077 != 0 {
+		return "", fmt.Errorf("%w: %s has mode %s, run 'chmod 600 %s'", ErrSessionFileInsecure, path, perm, path)
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read session file: %w", err)
+	}
+
+	session := strings.TrimPrefix(strings.TrimSpace(string(contents)), "session=")
+	if session == "" {
+		return "", fmt.Errorf("session file %s is empty", path)
+	}
+	return session, nil
+}
+
+// SessionInfo describes the account a session cookie belongs to.
+type SessionInfo struct {
+	// User is the name shown in the site header. Users without a display name
+	// are shown as "(anonymous user #<id>)".
+	User string
+
+	// Stars is the number of stars the user has collected in the year checked.
+	Stars int
+}
+
+var (
+	userPattern      = regexp.MustCompile(`(?s)<div class="user">(.*?)</div>`)
+	starCountPattern = regexp.MustCompile(`(?s)<span class="star-count">\s*(\d+)\*\s*</span>`)
+)
+
+// CheckSession confirms that AoC accepts the session cookie by loading the
+// calendar for the year, and reports who it belongs to. AoC serves the page
+// either way, so a session that isn't accepted is spotted by the header not
+// naming a user.
+func (f Fetcher) CheckSession(ctx context.Context, year int) (SessionInfo, error) {
+	body, err := f.get(ctx, f.url("%d", year))
+	if err != nil {
+		return SessionInfo{}, fmt.Errorf("failed to load calendar: %w", err)
+	}
+
+	info, ok := parseSessionInfo(string(body))
+	if !ok {
+		return SessionInfo{}, SessionError{}
+	}
+	return info, nil
+}
+
+// parseSessionInfo scrapes the user's name and star count from the header of an
+// AoC page.
+func parseSessionInfo(page string) (SessionInfo, bool) {
+	m := userPattern.FindStringSubmatch(page)
+	if m == nil {
+		return SessionInfo{}, false
+	}
+
+	var info SessionInfo
+	if s := starCountPattern.FindStringSubmatch(m[1]); s != nil {
+		info.Stars, _ = strconv.Atoi(s[1])
+	}
+	info.User = textContent(starCountPattern.ReplaceAllString(m[1], ""))
+	return info, info.User != ""
+}
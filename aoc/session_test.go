@@ -0,0 +1,87 @@
+package aoc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadSessionFile(t *testing.T) {
+	dir := t.TempDir()
+
+	write := func(name, contents string, perm os.FileMode) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(contents), perm))
+		require.NoError(t, os.Chmod(path, perm))
+		return path
+	}
+
+	session, err := ReadSessionFile(write("plain", "abc123\n", 0o600))
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", session)
+
+	session, err = ReadSessionFile(write("copied", "session=abc123", 0o600))
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", session)
+
+	_, err = ReadSessionFile(write("empty", " \n", 0o600))
+	assert.Error(t, err)
+
+	_, err = ReadSessionFile(filepath.Join(dir, "missing"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	if runtime.GOOS != "windows" {
+		_, err = ReadSessionFile(write("shared", "abc123", 0o644))
+		assert.ErrorIs(t, err, ErrSessionFileInsecure)
+	}
+}
+
+func TestParseSessionInfo(t *testing.T) {
+	page, err := os.ReadFile("testdata/day01.html")
+	require.NoError(t, err)
+
+	info, ok := parseSessionInfo(string(page))
+	require.True(t, ok)
+	assert.Equal(t, SessionInfo{User: "Example User", Stars: 2}, info)
+
+	info, ok = parseSessionInfo(`<div class="user">(anonymous user #123456)</div>`)
+	require.True(t, ok)
+	assert.Equal(t, SessionInfo{User: "(anonymous user #123456)"}, info)
+
+	_, ok = parseSessionInfo(`<nav><ul><li><a href="/2022/auth/login">[Log In]</a></li></ul></nav>`)
+	assert.False(t, ok)
+}
+
+func TestCheckSession(t *testing.T) {
+	page, err := os.ReadFile("testdata/day01.html")
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/2022", r.URL.Path)
+
+		if c, err := r.Cookie("session"); err != nil || c.Value != testSession {
+			_, _ = w.Write([]byte(`<header><a href="/2022/auth/login">[Log In]</a></header>`))
+			return
+		}
+		_, _ = w.Write(page)
+	}))
+	defer srv.Close()
+
+	f := testFetcher(t, srv)
+
+	info, err := f.CheckSession(context.Background(), 2022)
+	require.NoError(t, err)
+	assert.Equal(t, "Example User", info.User)
+
+	f.client.Jar.SetCookies(f.root, []*http.Cookie{{Name: "session", Value: "expired"}})
+
+	_, err = f.CheckSession(context.Background(), 2022)
+	assert.ErrorIs(t, err, ErrSessionInvalid)
+}
@@ -0,0 +1,304 @@
+package aoc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const statsPathPattern = "%d/leaderboard/self"
+
+// StatsRefreshInterval is how long fetched PersonalStats are reused before
+// being fetched again.
+const StatsRefreshInterval = 15 * time.Minute
+
+// PersonalStats are the times, ranks and scores shown on the personal
+// leaderboard page for a year.
+type PersonalStats struct {
+	Year      int        `json:"year"`
+	FetchedAt time.Time  `json:"fetchedAt"`
+	Days      []DayStats `json:"days"` // Days is in order of day.
+}
+
+// DayStats are the personal stats for a single day. Parts that haven't been
+// solved are nil.
+type DayStats struct {
+	Day     int        `json:"day"`
+	PartOne *PartStats `json:"partOne,omitempty"`
+	PartTwo *PartStats `json:"partTwo,omitempty"`
+}
+
+// Part returns the stats for part 1 or 2 of the day, or nil if it hasn't been
+// solved.
+func (d DayStats) Part(part int) *PartStats {
+	if part == 1 {
+		return d.PartOne
+	}
+	return d.PartTwo
+}
+
+// PartStats are the personal stats for one part of a day.
+type PartStats struct {
+	// Time is how long after the puzzle unlocked the part was solved. AoC only
+	// shows times within a day, so for parts solved later it is zero and
+	// OverADay is set.
+	Time     time.Duration `json:"time"`
+	OverADay bool          `json:"overADay,omitempty"`
+
+	Rank  int `json:"rank"`
+	Score int `json:"score"`
+}
+
+// Day returns the stats for the day, and whether there are any.
+func (s PersonalStats) Day(day int) (DayStats, bool) {
+	for _, d := range s.Days {
+		if d.Day == day {
+			return d, true
+		}
+	}
+	return DayStats{}, false
+}
+
+// FetchPersonalStats returns the personal stats for the year. They are cached
+// locally, and only fetched from AoC again once older than
+// StatsRefreshInterval. Each fetch that finds the stats changed also adds them
+// to the year's history, which PersonalStatsHistory returns.
+func (f Fetcher) FetchPersonalStats(ctx context.Context, year int) (PersonalStats, error) {
+	log := f.log.With(zap.Int("year", year))
+
+	cached, err := f.loadPersonalStats(year)
+	switch {
+	case err != nil:
+		log.Warn("failed to load cached stats, fetching from aoc", zap.Error(err))
+	case cached == nil:
+	case f.now().Sub(cached.FetchedAt) < StatsRefreshInterval:
+		log.Info("cached stats are recent enough, not fetching", zap.Time("fetchedAt", cached.FetchedAt))
+		return *cached, nil
+	}
+
+	body, err := f.get(ctx, f.url(statsPathPattern, year))
+	if err != nil {
+		if cached != nil {
+			log.Warn("failed to refresh stats, using cached version", zap.Error(err))
+			return *cached, nil
+		}
+		return PersonalStats{}, fmt.Errorf("failed to fetch personal stats: %w", err)
+	}
+
+	stats, err := parsePersonalStats(string(body))
+	if err != nil {
+		return PersonalStats{}, err
+	}
+	stats.Year, stats.FetchedAt = year, f.now()
+
+	if err := f.savePersonalStats(stats); err != nil {
+		log.Warn("failed to save stats to local folder", zap.Error(err))
+	}
+	if err := f.appendStatsHistory(ctx, stats, cached); err != nil {
+		log.Warn("failed to add stats to history", zap.Error(err))
+	}
+	return stats, nil
+}
+
+// PersonalStatsHistory returns every distinct snapshot of the year's personal
+// stats that has been fetched, oldest first.
+func (f Fetcher) PersonalStatsHistory(year int) ([]PersonalStats, error) {
+	return f.loadStatsHistory(year)
+}
+
+var (
+	statsPrePattern = regexp.MustCompile(`(?s)<pre>(.*?)</pre>`)
+
+	// statsRowPattern matches a day's row, which has a time, rank and score for
+	// each part solved, with "-" for each of them if part two isn't.
+	statsRowPattern = regexp.MustCompile(`^\s*(\d+)\s+(\S+)\s+(\S+)\s+(\S+)(?:\s+(\S+)\s+(\S+)\s+(\S+))?\s*$`)
+)
+
+// parsePersonalStats reads the table on the personal leaderboard page.
+func parsePersonalStats(page string) (PersonalStats, error) {
+	if _, ok := parseSessionInfo(page); !ok {
+		return PersonalStats{}, SessionError{}
+	}
+
+	var stats PersonalStats
+
+	m := statsPrePattern.FindStringSubmatch(page)
+	if m == nil {
+		// There's no table until the first star is collected.
+		return stats, nil
+	}
+
+	for _, line := range strings.Split(textLines(m[1]), "\n") {
+		row := statsRowPattern.FindStringSubmatch(line)
+		if row == nil {
+			continue
+		}
+
+		day, _ := strconv.Atoi(row[1])
+		d := DayStats{Day: day}
+
+		var err error
+		if d.PartOne, err = parsePartStats(row[2], row[3], row[4]); err != nil {
+			return PersonalStats{}, fmt.Errorf("day %d part 1: %w", day, err)
+		}
+		if row[5] != "" {
+			if d.PartTwo, err = parsePartStats(row[5], row[6], row[7]); err != nil {
+				return PersonalStats{}, fmt.Errorf("day %d part 2: %w", day, err)
+			}
+		}
+		stats.Days = append(stats.Days, d)
+	}
+
+	sort.Slice(stats.Days, func(i, j int) bool { return stats.Days[i].Day < stats.Days[j].Day })
+	return stats, nil
+}
+
+// parsePartStats parses the columns for one part, returning nil if it hasn't
+// been solved.
+func parsePartStats(timeCol, rankCol, scoreCol string) (*PartStats, error) {
+	if timeCol == "-" {
+		return nil, nil
+	}
+
+	var (
+		ps  PartStats
+		err error
+	)
+	if timeCol == ">24h" {
+		ps.OverADay = true
+	} else if ps.Time, err = parseClock(timeCol); err != nil {
+		return nil, err
+	}
+
+	if ps.Rank, err = strconv.Atoi(rankCol); err != nil {
+		return nil, fmt.Errorf("invalid rank %q", rankCol)
+	}
+	if ps.Score, err = strconv.Atoi(scoreCol); err != nil {
+		return nil, fmt.Errorf("invalid score %q", scoreCol)
+	}
+	return &ps, nil
+}
+
+// parseClock parses a time given as hh:mm:ss.
+func parseClock(s string) (time.Duration, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+
+	var d time.Duration
+	for i, unit := range []time.Duration{time.Hour, time.Minute, time.Second} {
+		n, err := strconv.Atoi(parts[i])
+		if err != nil {
+			return 0, fmt.Errorf("invalid time %q", s)
+		}
+		d += time.Duration(n) * unit
+	}
+	return d, nil
+}
+
+// region filesystem
+
+func (f Fetcher) statsFileName(year int) string {
+	return filepath.Join(f.localFolder, strconv.Itoa(year), "stats.json")
+}
+
+func (f Fetcher) loadPersonalStats(year int) (*PersonalStats, error) {
+	data, err := os.ReadFile(f.statsFileName(year))
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to read stats file: %w", err)
+	}
+
+	var stats PersonalStats
+	if err := json.Unmarshal(data, &stats); err != nil {
+		return nil, fmt.Errorf("failed to parse stats file: %w", err)
+	}
+	return &stats, nil
+}
+
+func (f Fetcher) statsHistoryFileName(year int) string {
+	return filepath.Join(f.localFolder, strconv.Itoa(year), "stats-history.json")
+}
+
+func (f Fetcher) loadStatsHistory(year int) ([]PersonalStats, error) {
+	data, err := os.ReadFile(f.statsHistoryFileName(year))
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to read stats history: %w", err)
+	}
+
+	var history []PersonalStats
+	if err := json.Unmarshal(data, &history); err != nil {
+		return nil, fmt.Errorf("failed to parse stats history: %w", err)
+	}
+	return history, nil
+}
+
+// appendStatsHistory adds the stats to the year's history, unless they're the
+// same as the latest snapshot in it. previous is what was cached before the
+// stats were fetched, which starts the history if there isn't one yet so that
+// stats saved before it existed aren't lost.
+func (f Fetcher) appendStatsHistory(ctx context.Context, stats PersonalStats, previous *PersonalStats) error {
+	path := f.statsHistoryFileName(stats.Year)
+
+	lock, err := lockFile(ctx, path+".lock")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = lock.Unlock() }()
+
+	history, err := f.loadStatsHistory(stats.Year)
+	if err != nil {
+		return err
+	}
+	seeded := len(history) == 0 && previous != nil
+	if seeded {
+		history = append(history, *previous)
+	}
+
+	switch {
+	case len(history) == 0 || !reflect.DeepEqual(history[len(history)-1].Days, stats.Days):
+		history = append(history, stats)
+	case !seeded:
+		return nil
+	}
+
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode stats history: %w", err)
+	}
+	if err := writeFileAtomic(path, data); err != nil {
+		return fmt.Errorf("failed to write stats history: %w", err)
+	}
+	return nil
+}
+
+func (f Fetcher) savePersonalStats(stats PersonalStats) error {
+	data, err := json.MarshalIndent(stats, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode stats: %w", err)
+	}
+
+	if err := writeFileAtomic(f.statsFileName(stats.Year), data); err != nil {
+		return fmt.Errorf("failed to write stats file: %w", err)
+	}
+	return nil
+}
+
+// endregion
@@ -0,0 +1,156 @@
+package aoc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePersonalStats(t *testing.T) {
+	page, err := os.ReadFile("testdata/self.html")
+	require.NoError(t, err)
+
+	stats, err := parsePersonalStats(string(page))
+	require.NoError(t, err)
+	require.Len(t, stats.Days, 5)
+
+	day1, ok := stats.Day(1)
+	require.True(t, ok)
+	assert.Equal(t, &PartStats{Time: 2*time.Minute + 30*time.Second, Rank: 95, Score: 6}, day1.PartOne)
+	assert.Equal(t, &PartStats{Time: time.Hour + 3*time.Minute + 10*time.Second, Rank: 4321}, day1.PartTwo)
+
+	day5, ok := stats.Day(5)
+	require.True(t, ok)
+	assert.Equal(t, &PartStats{OverADay: true, Rank: 90210}, day5.PartOne)
+	assert.Nil(t, day5.PartTwo, "part two of day 5 hasn't been solved")
+
+	for i, d := range stats.Days {
+		assert.Equal(t, i+1, d.Day, "days should be in order")
+	}
+
+	_, ok = stats.Day(6)
+	assert.False(t, ok)
+}
+
+func TestParsePersonalStatsEdgeCases(t *testing.T) {
+	t.Parallel()
+
+	const header = `<div class="user">Example User</div>`
+
+	type testTable struct {
+		Name    string
+		Page    string
+		Days    int
+		WantErr error
+	}
+
+	for _, entry := range []testTable{
+		{Name: "logged out", Page: "<pre>  1   00:01:00  1  100</pre>", WantErr: ErrSessionInvalid},
+		{Name: "no stars yet", Page: header + "<p>You haven't collected any stars.</p>"},
+		{Name: "part one only", Page: header + "<pre>  1   00:01:00      1    100</pre>", Days: 1},
+		{Name: "bad time", Page: header + "<pre>  1   1:00      1    100   -   -   -</pre>", WantErr: errors.New("invalid time")},
+	} {
+		entry := entry
+		t.Run(entry.Name, func(t *testing.T) {
+			t.Parallel()
+
+			stats, err := parsePersonalStats(entry.Page)
+			switch {
+			case entry.WantErr == nil:
+				require.NoError(t, err)
+			case errors.Is(entry.WantErr, ErrSessionInvalid):
+				assert.ErrorIs(t, err, entry.WantErr)
+			default:
+				assert.ErrorContains(t, err, entry.WantErr.Error())
+			}
+			assert.Len(t, stats.Days, entry.Days)
+		})
+	}
+}
+
+func TestFetchPersonalStatsRespectsRefreshInterval(t *testing.T) {
+	page, err := os.ReadFile("testdata/self.html")
+	require.NoError(t, err)
+
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "/2022/leaderboard/self", r.URL.Path)
+		_, _ = w.Write(page)
+	}))
+	defer srv.Close()
+
+	now := time.Date(2022, time.December, 5, 12, 0, 0, 0, time.UTC)
+	f := testFetcher(t, srv)
+	f.now = func() time.Time { return now }
+
+	stats, err := f.FetchPersonalStats(context.Background(), 2022)
+	require.NoError(t, err)
+	assert.Len(t, stats.Days, 5)
+	assert.Equal(t, 2022, stats.Year)
+
+	now = now.Add(StatsRefreshInterval - time.Second)
+	cached, err := f.FetchPersonalStats(context.Background(), 2022)
+	require.NoError(t, err)
+	assert.Equal(t, stats.Days, cached.Days, "the saved stats should round trip")
+	assert.Equal(t, 1, requests, "the stats shouldn't be fetched again within the refresh interval")
+
+	now = now.Add(2 * time.Second)
+	stats, err = f.FetchPersonalStats(context.Background(), 2022)
+	require.NoError(t, err)
+	assert.Equal(t, 2, requests)
+	assert.Equal(t, now, stats.FetchedAt)
+}
+
+func TestPersonalStatsHistory(t *testing.T) {
+	page, err := os.ReadFile("testdata/self.html")
+	require.NoError(t, err)
+	changed := strings.Replace(string(page), "90210", "90000", 1)
+
+	serve := string(page)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, serve)
+	}))
+	defer srv.Close()
+
+	now := time.Date(2022, time.December, 5, 12, 0, 0, 0, time.UTC)
+	f := testFetcher(t, srv)
+	f.now = func() time.Time { return now }
+
+	// Stats saved before the history was kept should start it.
+	old, err := parsePersonalStats(string(page))
+	require.NoError(t, err)
+	old.Year, old.FetchedAt = 2022, now.Add(-time.Hour)
+	require.NoError(t, f.savePersonalStats(old))
+
+	_, err = f.FetchPersonalStats(context.Background(), 2022)
+	require.NoError(t, err)
+	history, err := f.PersonalStatsHistory(2022)
+	require.NoError(t, err)
+	require.Len(t, history, 1, "unchanged stats shouldn't be added again")
+	assert.Equal(t, old.FetchedAt, history[0].FetchedAt)
+
+	for i := 0; i < 2; i++ {
+		serve, now = changed, now.Add(StatsRefreshInterval)
+		_, err = f.FetchPersonalStats(context.Background(), 2022)
+		require.NoError(t, err)
+	}
+
+	history, err = f.PersonalStatsHistory(2022)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, now.Add(-StatsRefreshInterval), history[1].FetchedAt, "only the first fetch of the change should be kept")
+
+	day5, ok := history[1].Day(5)
+	require.True(t, ok)
+	assert.Equal(t, 90000, day5.PartOne.Rank)
+}
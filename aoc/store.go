@@ -0,0 +1,169 @@
+package aoc
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// An InputStore keeps hold of puzzle inputs once they have been fetched, so
+// that AoC is only asked for each one once.
+type InputStore interface {
+	// Has reports whether the input for the Puzzle has been saved.
+	Has(p Puzzle) (bool, error)
+
+	// Load returns the saved input for the Puzzle. It returns an error wrapping
+	// os.ErrNotExist if there isn't one.
+	Load(p Puzzle) (string, error)
+
+	// Save stores the input for the Puzzle, replacing any saved before.
+	Save(p Puzzle, input string) error
+
+	// Delete removes the saved input for the Puzzle, if there is one.
+	Delete(p Puzzle) error
+}
+
+// WithInputStore sets where the Fetcher keeps inputs. By default they are saved
+// as plain files in the local folder.
+func WithInputStore(s InputStore) FetcherOption {
+	return func(f *Fetcher) { f.store = s }
+}
+
+// region filesystem
+
+// FileStore saves each input as a plain file at <dir>/<year>/<day>.
+type FileStore struct {
+	log *zap.Logger
+	dir string
+}
+
+// NewFileStore creates a FileStore saving inputs in dir.
+func NewFileStore(log *zap.Logger, dir string) FileStore {
+	return FileStore{log: log.Named("file-store"), dir: dir}
+}
+
+func (s FileStore) path(p Puzzle) string {
+	return filepath.Join(s.dir, filepath.FromSlash(p.path()))
+}
+
+func (s FileStore) Has(p Puzzle) (bool, error) {
+	s.migrateLegacyInput(p)
+
+	_, err := os.Stat(s.path(p))
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("unable to check if input for day exists: %w", err)
+	default:
+		return true, nil
+	}
+}
+
+func (s FileStore) Load(p Puzzle) (string, error) {
+	s.migrateLegacyInput(p)
+
+	input, err := os.ReadFile(s.path(p))
+	if err != nil {
+		return "", fmt.Errorf("failed to read input file for day: %w", err)
+	}
+	return string(input), nil
+}
+
+func (s FileStore) Save(p Puzzle, input string) error {
+	err := writeFileAtomic(s.path(p), []byte(input))
+	if err != nil {
+		return fmt.Errorf("failed to write input file for day: %w", err)
+	}
+	return nil
+}
+
+func (s FileStore) Delete(p Puzzle) error {
+	err := os.Remove(s.path(p))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete input file for day: %w", err)
+	}
+	return nil
+}
+
+// migrateLegacyInput moves a 2022 input from the flat layout used before
+// multiple years were supported into its year folder.
+func (s FileStore) migrateLegacyInput(p Puzzle) {
+	if p.Year != DefaultYear {
+		return
+	}
+
+	legacy := filepath.Join(s.dir, fmt.Sprintf("%02d", p.Day))
+	if info, err := os.Stat(legacy); err != nil || info.IsDir() {
+		return
+	}
+	if _, err := os.Stat(s.path(p)); err == nil {
+		return
+	}
+
+	err := os.MkdirAll(filepath.Dir(s.path(p)), dirPerm)
+	if err == nil {
+		err = os.Rename(legacy, s.path(p))
+	}
+	if err != nil {
+		s.log.Warn("failed to migrate input to year folder", zap.Stringer("puzzle", p), zap.Error(err))
+		return
+	}
+	s.log.Info("migrated input to year folder", zap.Stringer("puzzle", p))
+}
+
+// endregion
+
+// region memory
+
+// MemoryStore keeps inputs in memory only, for tests and one-off runs.
+type MemoryStore struct {
+	mu     sync.RWMutex
+	inputs map[Puzzle]string
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{inputs: map[Puzzle]string{}}
+}
+
+func (s *MemoryStore) Has(p Puzzle) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, ok := s.inputs[p]
+	return ok, nil
+}
+
+func (s *MemoryStore) Load(p Puzzle) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	input, ok := s.inputs[p]
+	if !ok {
+		return "", fmt.Errorf("no input saved for %s: %w", p, os.ErrNotExist)
+	}
+	return input, nil
+}
+
+func (s *MemoryStore) Save(p Puzzle, input string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.inputs[p] = input
+	return nil
+}
+
+func (s *MemoryStore) Delete(p Puzzle) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.inputs, p)
+	return nil
+}
+
+// endregion
@@ -0,0 +1,205 @@
+package aoc
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Inputs are encrypted with AES-256-GCM, using a key derived from a passphrase
+// with PBKDF2-HMAC-SHA256. Each stored input is a header line followed by the
+// base64 of salt|nonce|ciphertext, so that it survives being committed and
+// diffed as text.
+
+const (
+	encryptedHeader = "aoc2022-encrypted-input v1\n"
+
+	// pbkdf2Iterations follows the OWASP recommendation for PBKDF2-HMAC-SHA256.
+	pbkdf2Iterations = 600_000
+	saltLen          = 16
+	keyLen           = 32
+)
+
+var (
+	// ErrNotEncrypted is returned when an EncryptedStore loads an input that was
+	// saved without encryption.
+	ErrNotEncrypted = errors.New("input is not encrypted")
+
+	// ErrDecrypt is returned when an input can't be decrypted, which almost
+	// always means the passphrase is wrong.
+	ErrDecrypt = errors.New("failed to decrypt input, check the passphrase")
+)
+
+// EncryptedStore encrypts inputs before handing them to another InputStore,
+// so that they can be kept somewhere they might be seen - such as a private
+// repository - without publishing them.
+type EncryptedStore struct {
+	inner      InputStore
+	passphrase []byte
+	iterations int
+
+	// Deriving a key is deliberately slow, so keys are kept for each salt seen.
+	// Inputs saved by this store all share one salt.
+	mu   sync.Mutex
+	salt []byte
+	keys map[string][]byte
+}
+
+// NewEncryptedStore creates an EncryptedStore saving to inner.
+func NewEncryptedStore(inner InputStore, passphrase string) (*EncryptedStore, error) {
+	if passphrase == "" {
+		return nil, errors.New("a passphrase is required to encrypt inputs")
+	}
+
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	return &EncryptedStore{
+		inner:      inner,
+		passphrase: []byte(passphrase),
+		iterations: pbkdf2Iterations,
+		salt:       salt,
+		keys:       map[string][]byte{},
+	}, nil
+}
+
+func (s *EncryptedStore) Has(p Puzzle) (bool, error) {
+	return s.inner.Has(p)
+}
+
+func (s *EncryptedStore) Load(p Puzzle) (string, error) {
+	stored, err := s.inner.Load(p)
+	if err != nil {
+		return "", err
+	}
+
+	if !strings.HasPrefix(stored, encryptedHeader) {
+		return "", fmt.Errorf("%s: %w", p, ErrNotEncrypted)
+	}
+
+	encoded := strings.TrimSpace(strings.TrimPrefix(stored, encryptedHeader))
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w: %s", p, ErrDecrypt, err)
+	}
+	if len(data) < saltLen {
+		return "", fmt.Errorf("%s: %w: too short", p, ErrDecrypt)
+	}
+
+	gcm, err := s.cipher(data[:saltLen])
+	if err != nil {
+		return "", err
+	}
+
+	data = data[saltLen:]
+	if len(data) < gcm.NonceSize() {
+		return "", fmt.Errorf("%s: %w: too short", p, ErrDecrypt)
+	}
+
+	// The puzzle is authenticated too, so that an input can't be passed off as
+	// another day's by renaming its file.
+	plain, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], []byte(p.String()))
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", p, ErrDecrypt)
+	}
+	return string(plain), nil
+}
+
+// Encrypt re-saves an input that was saved to the inner store without
+// encryption, such as before a passphrase was set, encrypting it. Inputs that
+// are already encrypted are left as they are.
+func (s *EncryptedStore) Encrypt(p Puzzle) error {
+	stored, err := s.inner.Load(p)
+	if err != nil {
+		return err
+	}
+	if strings.HasPrefix(stored, encryptedHeader) {
+		return nil
+	}
+	return s.Save(p, stored)
+}
+
+func (s *EncryptedStore) Save(p Puzzle, input string) error {
+	gcm, err := s.cipher(s.salt)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	data := append(append([]byte{}, s.salt...), nonce...)
+	data = gcm.Seal(data, nonce, []byte(input), []byte(p.String()))
+
+	return s.inner.Save(p, encryptedHeader+base64.StdEncoding.EncodeToString(data)+"\n")
+}
+
+func (s *EncryptedStore) Delete(p Puzzle) error {
+	return s.inner.Delete(p)
+}
+
+// cipher returns the AEAD for the key derived with the salt.
+func (s *EncryptedStore) cipher(salt []byte) (cipher.AEAD, error) {
+	s.mu.Lock()
+	key, ok := s.keys[string(salt)]
+	if !ok {
+		key = pbkdf2(s.passphrase, salt, s.iterations, keyLen)
+		s.keys[string(salt)] = key
+	}
+	s.mu.Unlock()
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init cipher: %w", err)
+	}
+	return gcm, nil
+}
+
+// pbkdf2 derives a key of keyLen bytes from the password and salt, as described
+// by RFC 8018 using HMAC-SHA256 as the pseudorandom function.
+func pbkdf2(password, salt []byte, iterations, keyLen int) []byte {
+	prf := hmac.New(sha256.New, password)
+	hashLen := prf.Size()
+	blocks := (keyLen + hashLen - 1) / hashLen
+
+	var (
+		key     = make([]byte, 0, blocks*hashLen)
+		u       = make([]byte, hashLen)
+		counter [4]byte
+	)
+	for block := 1; block <= blocks; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		binary.BigEndian.PutUint32(counter[:], uint32(block))
+		prf.Write(counter[:])
+		key = prf.Sum(key)
+
+		t := key[len(key)-hashLen:]
+		copy(u, t)
+		for i := 1; i < iterations; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range u {
+				t[j] ^= u[j]
+			}
+		}
+	}
+	return key[:keyLen]
+}
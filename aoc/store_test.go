@@ -0,0 +1,258 @@
+package aoc
+
+import (
+	"context"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// testEncryptedStore creates an EncryptedStore that derives keys quickly, as
+// the real iteration count would make the tests crawl.
+func testEncryptedStore(t *testing.T, inner InputStore, passphrase string) *EncryptedStore {
+	t.Helper()
+
+	s, err := NewEncryptedStore(inner, passphrase)
+	require.NoError(t, err)
+	s.iterations = 10
+	return s
+}
+
+func TestInputStores(t *testing.T) {
+	testTable := []struct {
+		Name string
+
+		New func(t *testing.T) InputStore
+	}{
+		{
+			Name: "file",
+			New:  func(t *testing.T) InputStore { return NewFileStore(zap.NewNop(), t.TempDir()) },
+		},
+		{
+			Name: "memory",
+			New:  func(t *testing.T) InputStore { return NewMemoryStore() },
+		},
+		{
+			Name: "encrypted",
+			New: func(t *testing.T) InputStore {
+				return testEncryptedStore(t, NewFileStore(zap.NewNop(), t.TempDir()), "hunter2")
+			},
+		},
+	}
+
+	for _, entry := range testTable {
+		entry := entry
+		t.Run(
+			entry.Name,
+			func(t *testing.T) {
+				t.Parallel()
+
+				s := entry.New(t)
+				p := Puzzle{Year: 2022, Day: 1}
+
+				ok, err := s.Has(p)
+				require.NoError(t, err)
+				assert.False(t, ok)
+
+				_, err = s.Load(p)
+				assert.ErrorIs(t, err, os.ErrNotExist)
+
+				require.NoError(t, s.Save(p, day01ExampleInput))
+
+				ok, err = s.Has(p)
+				require.NoError(t, err)
+				assert.True(t, ok)
+
+				input, err := s.Load(p)
+				require.NoError(t, err)
+				assert.Equal(t, day01ExampleInput, input)
+
+				ok, err = s.Has(Puzzle{Year: 2021, Day: 1})
+				require.NoError(t, err)
+				assert.False(t, ok, "inputs should be kept per year")
+			},
+		)
+	}
+}
+
+func TestFileStoreMigratesLegacyInputs(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "01"), []byte("legacy"), 0o600))
+
+	input, err := NewFileStore(zap.NewNop(), dir).Load(Puzzle{Year: 2022, Day: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "legacy", input)
+	assert.FileExists(t, filepath.Join(dir, "2022", "01"))
+}
+
+func TestEncryptedStore(t *testing.T) {
+	var (
+		inner = NewMemoryStore()
+		s     = testEncryptedStore(t, inner, "hunter2")
+		p     = Puzzle{Year: 2022, Day: 1}
+	)
+	require.NoError(t, s.Save(p, day01ExampleInput))
+
+	stored, err := inner.Load(p)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(stored, encryptedHeader))
+	assert.NotContains(t, stored, "1000")
+
+	t.Run(
+		"another store with the same passphrase",
+		func(t *testing.T) {
+			input, err := testEncryptedStore(t, inner, "hunter2").Load(p)
+			require.NoError(t, err)
+			assert.Equal(t, day01ExampleInput, input)
+		},
+	)
+
+	t.Run(
+		"wrong passphrase",
+		func(t *testing.T) {
+			_, err := testEncryptedStore(t, inner, "hunter3").Load(p)
+			assert.ErrorIs(t, err, ErrDecrypt)
+		},
+	)
+
+	t.Run(
+		"moved to another day",
+		func(t *testing.T) {
+			other := Puzzle{Year: 2022, Day: 2}
+			require.NoError(t, inner.Save(other, stored))
+
+			_, err := s.Load(other)
+			assert.ErrorIs(t, err, ErrDecrypt)
+		},
+	)
+
+	t.Run(
+		"not encrypted",
+		func(t *testing.T) {
+			plain := Puzzle{Year: 2022, Day: 3}
+			require.NoError(t, inner.Save(plain, "vJrwpWtwJgWrhcsFMMfFFhFp\n"))
+
+			_, err := s.Load(plain)
+			assert.ErrorIs(t, err, ErrNotEncrypted)
+		},
+	)
+}
+
+func TestPBKDF2(t *testing.T) {
+	testTable := []struct {
+		Name string
+
+		Password   string
+		Salt       string
+		Iterations int
+		KeyLen     int
+		Want       string
+	}{
+		{
+			Name:     "one iteration",
+			Password: "password", Salt: "salt", Iterations: 1, KeyLen: 32,
+			Want: "120fb6cffcf8b32c43e7225256c4f837a86548c92ccc35480805987cb70be17b",
+		},
+		{
+			Name:     "two iterations",
+			Password: "password", Salt: "salt", Iterations: 2, KeyLen: 32,
+			Want: "ae4d0c95af6b46d32d0adff928f06dd02a303f8ef3c251dfd6e2d85a95474c43",
+		},
+		{
+			Name:     "4096 iterations",
+			Password: "password", Salt: "salt", Iterations: 4096, KeyLen: 32,
+			Want: "c5e478d59288c841aa530db6845c4c8d962893a001ce4e11a4963873aa98134a",
+		},
+		{
+			Name:     "multiple blocks",
+			Password: "passwordPASSWORDpassword", Salt: "saltSALTsaltSALTsaltSALTsaltSALTsalt", Iterations: 4096, KeyLen: 40,
+			Want: "348c89dbcbd32b2f32d814b8116e84cf2b17347ebc1800181c4e2a1fb8dd53e1c635518c7dac47e9",
+		},
+	}
+
+	for _, entry := range testTable {
+		entry := entry
+		t.Run(
+			entry.Name,
+			func(t *testing.T) {
+				t.Parallel()
+
+				got := pbkdf2([]byte(entry.Password), []byte(entry.Salt), entry.Iterations, entry.KeyLen)
+				assert.Equal(t, entry.Want, hex.EncodeToString(got))
+			},
+		)
+	}
+}
+
+func TestFetchInputUsesStore(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = w.Write([]byte(day01ExampleInput))
+	}))
+	defer srv.Close()
+
+	store := NewMemoryStore()
+	f := testFetcher(t, srv)
+	WithInputStore(store)(&f)
+
+	p := Puzzle{Year: 2022, Day: 1}
+	for i := 0; i < 2; i++ {
+		input, err := f.FetchInput(context.Background(), p)
+		require.NoError(t, err)
+		assert.Equal(t, day01ExampleInput, input)
+	}
+	assert.Equal(t, 1, requests, "the second fetch should come from the store")
+
+	saved, err := store.Load(p)
+	require.NoError(t, err)
+	assert.Equal(t, day01ExampleInput, saved)
+}
+
+func TestFetchInputEncryptsPlaintextInput(t *testing.T) {
+	var (
+		inner = NewMemoryStore()
+		p     = Puzzle{Year: 2022, Day: 1}
+	)
+	require.NoError(t, inner.Save(p, day01ExampleInput))
+
+	// Offline, so that fetching the input again would fail.
+	f, err := NewFetcher(zap.NewNop(), "", t.TempDir(), WithOffline(), WithInputStore(testEncryptedStore(t, inner, "hunter2")))
+	require.NoError(t, err)
+
+	input, err := f.FetchInput(context.Background(), p)
+	require.NoError(t, err)
+	assert.Equal(t, day01ExampleInput, input)
+
+	stored, err := inner.Load(p)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(stored, encryptedHeader), "the input should have been encrypted")
+}
+
+func TestRepairInputEncryptsPlaintextInput(t *testing.T) {
+	var (
+		inner = NewMemoryStore()
+		p     = Puzzle{Year: 2022, Day: 1}
+	)
+	require.NoError(t, inner.Save(p, day01ExampleInput))
+
+	f, err := NewFetcher(zap.NewNop(), "", t.TempDir(), WithOffline(), WithInputStore(testEncryptedStore(t, inner, "hunter2")))
+	require.NoError(t, err)
+
+	audits := f.AuditInputs()
+	require.Len(t, audits, 1)
+	require.ErrorIs(t, audits[0].Err, ErrNotEncrypted)
+	require.NoError(t, f.RepairInput(audits[0]))
+
+	audits = f.AuditInputs()
+	require.Len(t, audits, 1)
+	assert.NoError(t, audits[0].Err, "the input should be encrypted, with its metadata recorded")
+}
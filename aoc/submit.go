@@ -0,0 +1,206 @@
+package aoc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const answerPathPattern = "%d/day/%d/answer"
+
+// A Verdict is AoC's judgement on a submitted answer.
+type Verdict int
+
+const (
+	VerdictUnknown Verdict = iota
+	VerdictCorrect
+	VerdictTooHigh
+	VerdictTooLow
+	VerdictWrong // Wrong, but AoC didn't say in which direction.
+	VerdictRateLimited
+	VerdictAlreadySolved
+)
+
+func (v Verdict) String() string {
+	switch v {
+	case VerdictCorrect:
+		return "correct"
+	case VerdictTooHigh:
+		return "too high"
+	case VerdictTooLow:
+		return "too low"
+	case VerdictWrong:
+		return "wrong"
+	case VerdictRateLimited:
+		return "rate limited"
+	case VerdictAlreadySolved:
+		return "already solved"
+	}
+	return "unknown"
+}
+
+var verdictsByName = map[string]Verdict{
+	VerdictUnknown.String():       VerdictUnknown,
+	VerdictCorrect.String():       VerdictCorrect,
+	VerdictTooHigh.String():       VerdictTooHigh,
+	VerdictTooLow.String():        VerdictTooLow,
+	VerdictWrong.String():         VerdictWrong,
+	VerdictRateLimited.String():   VerdictRateLimited,
+	VerdictAlreadySolved.String(): VerdictAlreadySolved,
+}
+
+// MarshalText implements encoding.TextMarshaler, so that stored Verdicts are
+// readable.
+func (v Verdict) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (v *Verdict) UnmarshalText(text []byte) error {
+	res, ok := verdictsByName[string(text)]
+	if !ok {
+		return fmt.Errorf("unknown verdict %q", text)
+	}
+	*v = res
+	return nil
+}
+
+// IsWrong reports whether the Verdict means the answer is definitely not
+// correct.
+func (v Verdict) IsWrong() bool {
+	return v == VerdictTooHigh || v == VerdictTooLow || v == VerdictWrong
+}
+
+// A SubmitResult is the parsed response to submitting an answer.
+type SubmitResult struct {
+	Verdict Verdict
+
+	// Wait is how long AoC asked us to wait before submitting again. It is set
+	// when rate limited, and after most wrong answers.
+	Wait time.Duration
+
+	// Message is the text of AoC's response, for displaying to the user.
+	Message string
+}
+
+// Submit posts the answer for the given part (1 or 2) of a day to AoC and
+// reports its verdict. Answers that the Ledger knows will be rejected are not
+// sent, and every verdict received is recorded in it.
+func (f Fetcher) Submit(ctx context.Context, p Puzzle, part int, answer Answer) (SubmitResult, error) {
+	if part != 1 && part != 2 {
+		return SubmitResult{}, fmt.Errorf("part must be 1 or 2, got %d", part)
+	}
+	if answer.IsZero() {
+		return SubmitResult{}, fmt.Errorf("refusing to submit an empty answer")
+	}
+	if err := f.ledger.Check(p, part, answer); err != nil {
+		return SubmitResult{}, fmt.Errorf("refusing to submit: %w", err)
+	}
+	if err := f.checkUnlocked(p); err != nil {
+		return SubmitResult{}, err
+	}
+
+	u := f.url(answerPathPattern, p.Year, p.Day)
+	f.log.Debug(
+		"submitting answer",
+		zap.String("url", u),
+		zap.Int("part", part),
+		zap.Stringer("answer", answer),
+	)
+
+	form := url.Values{
+		"level":  {strconv.Itoa(part)},
+		"answer": {answer.String()},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, strings.NewReader(form.Encode()))
+	if err != nil {
+		return SubmitResult{}, fmt.Errorf("failed to create http request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	// Submitting isn't idempotent - a retry after a lost response would count
+	// as a second attempt - so failures are never retried.
+	body, err := f.send(req, false)
+	if err != nil {
+		return SubmitResult{}, fmt.Errorf("failed to submit answer: %w", err)
+	}
+
+	result, err := parseSubmitResponse(string(body))
+	if err != nil {
+		return SubmitResult{}, err
+	}
+
+	if err := f.ledger.Record(p, part, answer, result, f.now()); err != nil {
+		f.log.Warn("failed to record verdict in ledger", zap.Error(err))
+	}
+	return result, nil
+}
+
+// Ledger returns the record of answers submitted through the Fetcher.
+func (f Fetcher) Ledger() *Ledger {
+	return f.ledger
+}
+
+var (
+	rateLimitWaitPattern = regexp.MustCompile(`You have (?:(\d+)m )?(\d+)s left to wait`)
+	wrongWaitPattern     = regexp.MustCompile(`[Pp]lease wait (one|\d+) minutes? before trying again`)
+)
+
+// parseSubmitResponse works out the Verdict from the HTML AoC sends back after
+// an answer is submitted.
+func parseSubmitResponse(page string) (SubmitResult, error) {
+	arts := articles(page)
+	if len(arts) == 0 {
+		return SubmitResult{}, fmt.Errorf("no <article> found in response")
+	}
+
+	res := SubmitResult{Message: textContent(arts[0])}
+	msg := res.Message
+
+	switch {
+	case strings.Contains(msg, "That's the right answer"):
+		res.Verdict = VerdictCorrect
+
+	case strings.Contains(msg, "That's not the right answer"):
+		switch {
+		case strings.Contains(msg, "your answer is too high"):
+			res.Verdict = VerdictTooHigh
+		case strings.Contains(msg, "your answer is too low"):
+			res.Verdict = VerdictTooLow
+		default:
+			res.Verdict = VerdictWrong
+		}
+
+		if m := wrongWaitPattern.FindStringSubmatch(msg); m != nil {
+			minutes := 1
+			if m[1] != "one" {
+				minutes, _ = strconv.Atoi(m[1])
+			}
+			res.Wait = time.Duration(minutes) * time.Minute
+		}
+
+	case strings.Contains(msg, "You gave an answer too recently"):
+		res.Verdict = VerdictRateLimited
+
+		if m := rateLimitWaitPattern.FindStringSubmatch(msg); m != nil {
+			minutes, _ := strconv.Atoi(m[1])
+			seconds, _ := strconv.Atoi(m[2])
+			res.Wait = time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second
+		}
+
+	case strings.Contains(msg, "You don't seem to be solving the right level"):
+		res.Verdict = VerdictAlreadySolved
+
+	default:
+		return res, fmt.Errorf("unrecognised response to submission: %q", msg)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,165 @@
+package aoc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const testSession = "test-session"
+
+// testFetcher creates a Fetcher that talks to srv rather than AoC.
+func testFetcher(t *testing.T, srv *httptest.Server) Fetcher {
+	t.Helper()
+
+	root, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	f, err := NewFetcher(
+		zap.NewNop(),
+		testSession,
+		t.TempDir(),
+		WithBaseURL(root),
+		WithMinInterval(0),
+		WithRetries(DefaultRetryAttempts, time.Millisecond),
+	)
+	require.NoError(t, err)
+	return f
+}
+
+// answerPage wraps a message in the same markup AoC uses for its answer page.
+func answerPage(msg string) string {
+	return fmt.Sprintf(
+		`<!DOCTYPE html><html><body><main><article><p>%s</p></article></main></body></html>`,
+		msg,
+	)
+}
+
+func TestSubmit(t *testing.T) {
+	testTable := []struct {
+		Name string
+
+		Response string
+		Verdict  Verdict
+		Wait     time.Duration
+	}{
+		{
+			Name:     "correct",
+			Response: `That's the right answer!  You are <span class="day-success">one gold star</span> closer to collecting enough star fruit. [<a href="/2022/day/1#part2">Continue to Part Two</a>]`,
+			Verdict:  VerdictCorrect,
+		},
+		{
+			Name:     "too high",
+			Response: `That's not the right answer; your answer is too high.  If you're stuck, make sure you're using the full input data; there are also some general tips on the <a href="/2022/about">about page</a>. Please wait one minute before trying again. [<a href="/2022/day/1">Return to Day 1</a>]`,
+			Verdict:  VerdictTooHigh,
+			Wait:     time.Minute,
+		},
+		{
+			Name:     "too low",
+			Response: `That's not the right answer; your answer is too low.  If you're stuck, make sure you're using the full input data. Please wait 5 minutes before trying again. [<a href="/2022/day/1">Return to Day 1</a>]`,
+			Verdict:  VerdictTooLow,
+			Wait:     5 * time.Minute,
+		},
+		{
+			Name:     "wrong",
+			Response: `That's not the right answer.  If you're stuck, make sure you're using the full input data. Please wait one minute before trying again. [<a href="/2022/day/5">Return to Day 5</a>]`,
+			Verdict:  VerdictWrong,
+			Wait:     time.Minute,
+		},
+		{
+			Name:     "rate limited",
+			Response: `You gave an answer too recently; you have to wait after submitting an answer before trying again.  You have 4m 27s left to wait. [<a href="/2022/day/1">Return to Day 1</a>]`,
+			Verdict:  VerdictRateLimited,
+			Wait:     4*time.Minute + 27*time.Second,
+		},
+		{
+			Name:     "rate limited seconds only",
+			Response: `You gave an answer too recently; you have to wait after submitting an answer before trying again.  You have 35s left to wait. [<a href="/2022/day/1">Return to Day 1</a>]`,
+			Verdict:  VerdictRateLimited,
+			Wait:     35 * time.Second,
+		},
+		{
+			Name:     "already solved",
+			Response: `You don't seem to be solving the right level.  Did you already complete it? [<a href="/2022/day/1">Return to Day 1</a>]`,
+			Verdict:  VerdictAlreadySolved,
+		},
+	}
+
+	for _, entry := range testTable {
+		entry := entry
+		t.Run(
+			entry.Name,
+			func(t *testing.T) {
+				t.Parallel()
+
+				srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					assert.Equal(t, http.MethodPost, r.Method)
+					assert.Equal(t, "/2022/day/1/answer", r.URL.Path)
+
+					c, err := r.Cookie("session")
+					if assert.NoError(t, err) {
+						assert.Equal(t, testSession, c.Value)
+					}
+
+					assert.Equal(t, "2", r.PostFormValue("level"))
+					assert.Equal(t, "45000", r.PostFormValue("answer"))
+
+					_, _ = fmt.Fprint(w, answerPage(entry.Response))
+				}))
+				defer srv.Close()
+
+				var (
+					f   = testFetcher(t, srv)
+					p   = Puzzle{Year: 2022, Day: 1}
+					now = p.UnlockTime().Add(time.Hour)
+				)
+				f.now = func() time.Time { return now }
+
+				res, err := f.Submit(context.Background(), p, 2, IntAnswer(45000))
+				require.NoError(t, err)
+
+				assert.Equal(t, entry.Verdict, res.Verdict)
+				assert.Equal(t, entry.Wait, res.Wait)
+				assert.NotEmpty(t, res.Message)
+
+				e, ok := f.Ledger().Entry(p, 2)
+				require.True(t, ok)
+				require.Len(t, e.Attempts, 1)
+				assert.True(t, now.Equal(e.Attempts[0].At), "should be recorded at the Fetcher's time")
+			},
+		)
+	}
+}
+
+func TestSubmitErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/2022/day/2/answer" {
+			_, _ = fmt.Fprint(w, answerPage("Something new and unexpected"))
+			return
+		}
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	f := testFetcher(t, srv)
+
+	_, err := f.Submit(context.Background(), Puzzle{Year: 2022, Day: 1}, 1, IntAnswer(1))
+	assert.Error(t, err, "non-200 responses should fail")
+
+	_, err = f.Submit(context.Background(), Puzzle{Year: 2022, Day: 2}, 1, IntAnswer(1))
+	assert.Error(t, err, "unrecognised responses should fail")
+
+	_, err = f.Submit(context.Background(), Puzzle{Year: 2022, Day: 1}, 3, IntAnswer(1))
+	assert.Error(t, err, "invalid parts should fail")
+
+	_, err = f.Submit(context.Background(), Puzzle{Year: 2022, Day: 1}, 1, Answer{})
+	assert.Error(t, err, "empty answers should fail")
+}
@@ -0,0 +1,408 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"go.uber.org/zap"
+
+	"github.com/nightmarlin/aoc2022/aoc"
+	"github.com/nightmarlin/aoc2022/registry"
+)
+
+const programName = "aoc2022"
+
+// Exit codes, one per class of failure, so that scripts driving the CLI can
+// tell what went wrong without scraping the logs.
+const (
+	exitOK       = 0
+	exitFailure  = 1 // Anything not covered below.
+	exitUsage    = 2 // Bad flags or arguments - matches the flag package.
+	exitConfig   = 3 // Missing or invalid configuration, such as the session.
+	exitFetch    = 4 // The puzzle input could not be retrieved.
+	exitSolution = 5 // A Solution returned an error.
+	exitRejected = 6 // AoC did not accept a submitted answer.
+	exitMismatch = 7 // A Solution's answer differs from the golden answer.
+)
+
+// An exitError attaches an exit code to an error on its way back to main.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e exitError) Error() string { return e.err.Error() }
+func (e exitError) Unwrap() error { return e.err }
+
+func withExitCode(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return exitError{code: code, err: err}
+}
+
+func usageErrorf(format string, args ...any) error {
+	return withExitCode(exitUsage, fmt.Errorf(format, args...))
+}
+
+func exitCodeFor(err error) int {
+	var ee exitError
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, flag.ErrHelp):
+		return exitOK
+	case errors.As(err, &ee):
+		return ee.code
+	default:
+		return exitFailure
+	}
+}
+
+// A command is a single subcommand of the CLI.
+type command struct {
+	Name    string
+	Args    string // Args is the synopsis of positional arguments, for help output.
+	Summary string
+	Run     func(ctx context.Context, a *app, args []string) error
+}
+
+// commands are listed in the order they appear in the help output. It is
+// populated in init to avoid an initialization cycle with the help command.
+var commands []command
+
+func init() {
+	commands = []command{
+		runCommand,
+		fetchCommand,
+		readCommand,
+		submitCommand,
+		benchCommand,
+		verifyCommand,
+		leaderboardCommand,
+		statsCommand,
+		listCommand,
+		authCommand,
+		inputsCommand,
+		{
+			Name:    "help",
+			Args:    "[command]",
+			Summary: "show help for the program or a single command",
+			Run:     runHelp,
+		},
+	}
+}
+
+func findCommand(name string) (command, bool) {
+	for _, c := range commands {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return command{}, false
+}
+
+// app holds the global configuration shared between commands.
+type app struct {
+	log    *zap.Logger
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+
+	global *flag.FlagSet
+
+	sessionCookie string
+	sessionFile   string
+	accountName   string
+	accountsDir   string
+	localFolder   string
+	year          int
+	userAgent     string
+	baseURL       string
+	offline       bool
+	trace         bool
+}
+
+// session returns the session cookie, taken from -session if set and read from
+// the session file otherwise. It is empty if neither is set, as a session is
+// only needed once something has to be fetched.
+func (a *app) session() (string, error) {
+	if a.sessionCookie != "" {
+		return a.sessionCookie, nil
+	}
+
+	if a.sessionFile != "" {
+		session, err := aoc.ReadSessionFile(a.sessionFile)
+		switch {
+		case err == nil:
+			return session, nil
+		case !errors.Is(err, os.ErrNotExist):
+			return "", withExitCode(exitConfig, err)
+		}
+	}
+
+	// Without a session, the fetcher can still use whatever is saved locally.
+	return "", nil
+}
+
+// fetcher initialises an aoc.Fetcher from the global configuration, for the
+// account chosen with -account if there is one.
+func (a *app) fetcher() (aoc.Fetcher, error) {
+	if a.offline {
+		// Nothing will be sent to AoC, so there's no need for a session.
+		return a.localFetcher()
+	}
+	if a.accountName != "" {
+		acc, err := a.account(a.accountName)
+		if err != nil {
+			return aoc.Fetcher{}, err
+		}
+		return a.accountFetcher(acc)
+	}
+
+	session, err := a.session()
+	if err != nil {
+		return aoc.Fetcher{}, err
+	}
+	return a.newFetcher(session, a.localFolder)
+}
+
+// localFetcher initialises an aoc.Fetcher for commands that only look at the
+// local folder, and so don't need a session.
+func (a *app) localFetcher() (aoc.Fetcher, error) {
+	if a.accountName != "" {
+		acc, err := a.account(a.accountName)
+		if err != nil {
+			return aoc.Fetcher{}, err
+		}
+		return a.newFetcher("", acc.LocalFolder)
+	}
+	return a.newFetcher("", a.localFolder)
+}
+
+func (a *app) newFetcher(session, localFolder string) (aoc.Fetcher, error) {
+	opts := []aoc.FetcherOption{aoc.WithUserAgent(a.userAgent)}
+	base, err := url.Parse(a.baseURL)
+	if err != nil || (base.Scheme != "http" && base.Scheme != "https") || base.Host == "" {
+		return aoc.Fetcher{}, withExitCode(exitConfig, fmt.Errorf("invalid base url %q, it should look like %s", a.baseURL, aoc.RootURL))
+	}
+	opts = append(opts, aoc.WithBaseURL(base))
+	if a.offline {
+		opts = append(opts, aoc.WithOffline())
+	}
+	if passphrase := os.Getenv("AOC_INPUT_PASSPHRASE"); passphrase != "" {
+		store, err := aoc.NewEncryptedStore(aoc.NewFileStore(a.log, localFolder), passphrase)
+		if err != nil {
+			return aoc.Fetcher{}, withExitCode(exitConfig, err)
+		}
+		opts = append(opts, aoc.WithInputStore(store))
+	}
+
+	f, err := aoc.NewFetcher(a.log, session, localFolder, opts...)
+	if err != nil {
+		return aoc.Fetcher{}, withExitCode(exitConfig, fmt.Errorf("failed to init aoc fetcher: %w", err))
+	}
+	return f, nil
+}
+
+// fetchError wraps a failure to get something from AoC with the exit code for
+// its cause. A missing or expired session is a problem with the configuration
+// rather than with fetching.
+func fetchError(err error) error {
+	switch {
+	case errors.Is(err, aoc.ErrNoSession):
+		return withExitCode(
+			exitConfig,
+			fmt.Errorf("%w - set one with -session, SESSION_COOKIE or -session-file, or use -offline", err),
+		)
+	case errors.Is(err, aoc.ErrSessionInvalid):
+		return withExitCode(exitConfig, err)
+	}
+	return withExitCode(exitFetch, err)
+}
+
+// inputError is fetchError for a failure to get the input for a Puzzle.
+func inputError(p aoc.Puzzle, err error) error {
+	return fetchError(fmt.Errorf("unable to get input for %s: %w", p, err))
+}
+
+// flagSet creates a FlagSet for the named command whose help output matches the
+// rest of the CLI.
+func (a *app) flagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	fs.Usage = func() {
+		c, _ := findCommand(name)
+		_, _ = fmt.Fprintf(fs.Output(), "usage: %s [flags]\n\n%s\n", strings.TrimSpace(programName+" "+c.Name+" "+c.Args), c.Summary)
+
+		hasFlags := false
+		fs.VisitAll(func(*flag.Flag) { hasFlags = true })
+		if hasFlags {
+			_, _ = fmt.Fprintf(fs.Output(), "\nflags:\n")
+			fs.PrintDefaults()
+		}
+	}
+	return fs
+}
+
+// parseArgs parses the flags in args, allowing them to appear before, after or
+// between positional arguments, and returns the positional arguments.
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, withExitCode(exitUsage, err)
+		}
+
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// runCLI parses the global flags, then hands over to the chosen command.
+func runCLI(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	a := &app{stdin: os.Stdin, stdout: stdout, stderr: stderr}
+
+	global := flag.NewFlagSet(programName, flag.ContinueOnError)
+	a.global = global
+	global.SetOutput(stderr)
+	global.StringVar(&a.sessionCookie, "session", os.Getenv("SESSION_COOKIE"), "AoC session `cookie` (env SESSION_COOKIE)")
+	global.StringVar(&a.sessionFile, "session-file", envOr("AOC_SESSION_FILE", configPath("session")), "`file` to read the session cookie from when -session isn't set, which must only be readable by you (env AOC_SESSION_FILE)")
+	global.StringVar(&a.accountName, "account", os.Getenv("AOC_ACCOUNT"), "use the named `account`'s session and inputs, see the README (env AOC_ACCOUNT)")
+	global.StringVar(&a.accountsDir, "accounts-dir", envOr("AOC_ACCOUNTS_DIR", configPath("accounts")), "`dir`ectory holding a session file for each account (env AOC_ACCOUNTS_DIR)")
+	global.StringVar(&a.localFolder, "inputs", envOr("LOCAL_FOLDER", "inputs"), "`dir`ectory inputs are cached in (env LOCAL_FOLDER)")
+	global.IntVar(&a.year, "year", envIntOr("AOC_YEAR", aoc.DefaultYear), "the `year` days refer to when not given as <year>/<day> (env AOC_YEAR)")
+	global.StringVar(&a.userAgent, "user-agent", envOr("AOC_USER_AGENT", aoc.DefaultUserAgent), "the `User-Agent` sent to AoC, which should include your contact details (env AOC_USER_AGENT)")
+	global.StringVar(&a.baseURL, "base-url", envOr("AOC_BASE_URL", aoc.RootURL), "`url` AoC is served from, such as that of a fakeaoc server (env AOC_BASE_URL)")
+	global.BoolVar(&a.offline, "offline", os.Getenv("AOC_OFFLINE") != "", "never connect to AoC, only using what's already saved in the inputs directory (env AOC_OFFLINE)")
+	global.BoolVar(&a.trace, "trace", os.Getenv("TRACE") != "", "enable debug logging (env TRACE)")
+	global.Usage = func() { printUsage(global) }
+
+	if err := global.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	if global.NArg() == 0 {
+		printUsage(global)
+		return exitUsage
+	}
+
+	c, ok := findCommand(global.Arg(0))
+	if !ok {
+		_, _ = fmt.Fprintf(stderr, "%s: unknown command %q\n\n", programName, global.Arg(0))
+		printUsage(global)
+		return exitUsage
+	}
+
+	log, err := initLogger(a.trace)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "failed to init logger: %s\n", err.Error())
+		return exitFailure
+	}
+	defer func() { _ = log.Sync() }()
+	a.log = log
+
+	err = c.Run(ctx, a, global.Args()[1:])
+	code := exitCodeFor(err)
+	if code != exitOK {
+		_, _ = fmt.Fprintf(stderr, "%s %s: %s\n", programName, c.Name, err.Error())
+	}
+	return code
+}
+
+func printUsage(global *flag.FlagSet) {
+	out := global.Output()
+	_, _ = fmt.Fprintf(out, "usage: %s [global flags] <command> [args]\n\ncommands:\n", programName)
+
+	tw := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	for _, c := range commands {
+		_, _ = fmt.Fprintf(tw, "  %s\t%s\n", c.Name, c.Summary)
+	}
+	_ = tw.Flush()
+
+	_, _ = fmt.Fprintf(out, "\nglobal flags:\n")
+	global.PrintDefaults()
+	_, _ = fmt.Fprintf(out, "\nrun '%s help <command>' for more information on a command.\n", programName)
+}
+
+func runHelp(ctx context.Context, a *app, args []string) error {
+	if len(args) == 0 {
+		a.global.SetOutput(a.stdout)
+		printUsage(a.global)
+		return nil
+	}
+
+	c, ok := findCommand(args[0])
+	if !ok {
+		return usageErrorf("unknown command %q", args[0])
+	}
+	return c.Run(ctx, a, []string{"-h"})
+}
+
+// configPath returns the path of the named file in the program's folder of the
+// user's config directory, or "" if there is no config directory.
+func configPath(name string) string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, programName, name)
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func envIntOr(key string, fallback int) int {
+	if v, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return v
+	}
+	return fallback
+}
+
+// parsePuzzle parses a day argument such as "4" or "2021/04", using the year
+// chosen with -year when none is given.
+func (a *app) parsePuzzle(s string) (aoc.Puzzle, error) {
+	p, err := aoc.ParsePuzzle(s, a.year)
+	if err != nil {
+		return aoc.Puzzle{}, withExitCode(exitUsage, err)
+	}
+	return p, nil
+}
+
+// parseSolvedPuzzle is parsePuzzle for commands that need a Solution, returning
+// its registry.Entry.
+func (a *app) parseSolvedPuzzle(s string) (registry.Entry, error) {
+	p, err := a.parsePuzzle(s)
+	if err != nil {
+		return registry.Entry{}, err
+	}
+
+	e, ok := registry.Lookup(p)
+	if !ok {
+		return registry.Entry{}, usageErrorf("the solution for %s has not been completed or does not exist", p)
+	}
+	return e, nil
+}
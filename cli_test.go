@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
+	"github.com/nightmarlin/aoc2022/aoc"
+	"github.com/nightmarlin/aoc2022/fakeaoc"
+	"github.com/nightmarlin/aoc2022/registry"
+)
+
+func TestParseArgsInterspersed(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	part := fs.Int("part", 0, "")
+
+	args, err := parseArgs(fs, []string{"4", "--part", "2", "extra"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"4", "extra"}, args)
+	assert.Equal(t, 2, *part)
+}
+
+func TestExitCodes(t *testing.T) {
+	empty, cached := t.TempDir(), t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(cached, "2022"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(cached, "2022", "01"), []byte(day01ExampleInput), 0o600))
+
+	testTable := []struct {
+		Name string
+
+		Args []string
+		Code int
+	}{
+		{Name: "no command", Args: nil, Code: exitUsage},
+		{Name: "unknown command", Args: []string{"nope"}, Code: exitUsage},
+		{Name: "help", Args: []string{"help"}, Code: exitOK},
+		{Name: "command help", Args: []string{"run", "-h"}, Code: exitOK},
+		{Name: "bad day", Args: []string{"run", "banana"}, Code: exitUsage},
+		{Name: "bad part", Args: []string{"run", "1", "-part", "3"}, Code: exitUsage},
+		{Name: "missing session", Args: []string{"-session", "", "-session-file", "", "-inputs", empty, "fetch", "1"}, Code: exitConfig},
+		{Name: "missing session file", Args: []string{"-session", "", "-session-file", "does-not-exist", "-inputs", empty, "fetch", "1"}, Code: exitConfig},
+		{Name: "cached without session", Args: []string{"-session", "", "-session-file", "", "-inputs", cached, "fetch", "1"}, Code: exitOK},
+		{Name: "offline cache miss", Args: []string{"-offline", "-inputs", empty, "fetch", "1"}, Code: exitFetch},
+		{Name: "offline cache hit", Args: []string{"-offline", "-inputs", cached, "fetch", "1"}, Code: exitOK},
+		{Name: "bad auth subcommand", Args: []string{"auth", "login"}, Code: exitUsage},
+	}
+
+	for _, entry := range testTable {
+		entry := entry
+		t.Run(
+			entry.Name,
+			func(t *testing.T) {
+				var stdout, stderr bytes.Buffer
+				assert.Equal(t, entry.Code, runCLI(context.Background(), entry.Args, &stdout, &stderr))
+			},
+		)
+	}
+}
+
+func TestFetchErrorAmongSolutionErrors(t *testing.T) {
+	p := aoc.Puzzle{Year: 2022, Day: 1}
+
+	testTable := []struct {
+		Name string
+
+		Err  error
+		Code int
+	}{
+		{Name: "network", Err: aoc.NetworkError{Err: errors.New("connection reset")}, Code: exitFetch},
+		{Name: "session expired", Err: aoc.SessionError{StatusCode: http.StatusBadRequest}, Code: exitConfig},
+		{Name: "no session", Err: aoc.ErrNoSession, Code: exitConfig},
+	}
+
+	for _, entry := range testTable {
+		entry := entry
+		t.Run(
+			entry.Name,
+			func(t *testing.T) {
+				errs := multierr.Combine(
+					errors.New("2022/2 part 1: solution failed"),
+					inputError(p, entry.Err),
+				)
+
+				require.True(t, isFetchError(errs))
+				assert.Equal(t, entry.Code, exitCodeFor(fetchError(errs)))
+			},
+		)
+	}
+}
+
+// failingPuzzle has a solution that always fails, registered by the tests.
+var failingPuzzle = aoc.Puzzle{Year: 2015, Day: 1}
+
+// errFailingSolution is returned by both parts of failingSolution.
+var errFailingSolution = errors.New("failing solution")
+
+type failingSolution struct{}
+
+func (failingSolution) PartOne(context.Context, string) (aoc.Answer, error) {
+	return aoc.Answer{}, errFailingSolution
+}
+
+func (failingSolution) PartTwo(context.Context, string) (aoc.Answer, error) {
+	return aoc.Answer{}, errFailingSolution
+}
+
+func init() {
+	registry.Register(registry.Entry{
+		Puzzle: failingPuzzle,
+		Title:  "Always Fails",
+		New:    func(*zap.Logger) registry.Solution { return failingSolution{} },
+	})
+}
+
+func TestSubmitSolutionFailure(t *testing.T) {
+	srv := httptest.NewServer(fakeaoc.New(fakeaoc.Config{
+		Fixtures: "fakeaoc/testdata",
+		Sessions: map[string]string{"test-session": "Example User"},
+	}))
+	defer srv.Close()
+
+	inputs := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(inputs, "2015"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(inputs, "2015", "01"), []byte("(()\n"), 0o600))
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI(
+		context.Background(),
+		[]string{"-base-url", srv.URL, "-session", "test-session", "-inputs", inputs, "submit", failingPuzzle.String()},
+		&stdout, &stderr,
+	)
+	assert.Equal(t, exitSolution, code, stderr.String())
+	assert.Contains(t, stderr.String(), errFailingSolution.Error())
+}
@@ -0,0 +1,126 @@
+// Command fakeaoc serves a fake Advent of Code from a directory of fixtures, so
+// that the CLI can be tried out without a network or an AoC account:
+//
+//	go run ./cmd/fakeaoc &
+//	go run . -base-url http://localhost:8025/ -session fake-session run 1
+//
+// See the fakeaoc package for how the fixtures are laid out.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/nightmarlin/aoc2022/fakeaoc"
+)
+
+// defaultSession is the session cookie accepted when no -session is given.
+const defaultSession = "fake-session"
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	err := run(ctx)
+	stop()
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "fakeaoc: %s\n", err.Error())
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context) error {
+	var (
+		addr        = flag.String("addr", "localhost:8025", "`address` to listen on")
+		fixtures    = flag.String("fixtures", "fakeaoc/testdata", "`dir`ectory the puzzles are served from")
+		minInterval = flag.Duration("min-interval", time.Second, "least `time` allowed between requests with the same session, 0 to disable")
+		cooldown    = flag.Duration("cooldown", time.Minute, "how long to refuse answers for after a wrong one")
+		now         = flag.String("now", "", "pretend the server started at this RFC 3339 `time`, to try out puzzles before they unlock")
+		sessions    = map[string]string{}
+	)
+	flag.Func("session", "accept a session, given as `cookie=name` (may be repeated, defaults to "+defaultSession+"=Example User)", func(s string) error {
+		cookie, name, ok := strings.Cut(s, "=")
+		if !ok || cookie == "" || name == "" {
+			return errors.New("should look like cookie=name")
+		}
+		sessions[cookie] = name
+		return nil
+	})
+	flag.Parse()
+
+	if len(sessions) == 0 {
+		sessions[defaultSession] = "Example User"
+	}
+
+	cfg := fakeaoc.Config{
+		Fixtures:       *fixtures,
+		Sessions:       sessions,
+		MinInterval:    *minInterval,
+		AnswerCooldown: *cooldown,
+	}
+	if *now != "" {
+		start, err := time.Parse(time.RFC3339, *now)
+		if err != nil {
+			return fmt.Errorf("invalid -now: %w", err)
+		}
+		offset := time.Until(start)
+		cfg.Now = func() time.Time { return time.Now().Add(offset) }
+	}
+
+	log, err := zap.NewDevelopment()
+	if err != nil {
+		return fmt.Errorf("failed to init logger: %w", err)
+	}
+	defer func() { _ = log.Sync() }()
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           logRequests(log, fakeaoc.New(cfg)),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	log.Info("serving fake AoC", zap.String("url", "http://"+*addr+"/"), zap.String("fixtures", *fixtures))
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// statusRecorder remembers the status code written through it.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// logRequests logs each request h serves, and the status it was answered with.
+func logRequests(log *zap.Logger, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		h.ServeHTTP(rec, r)
+		log.Info(
+			"request",
+			zap.String("method", r.Method),
+			zap.String("path", r.URL.Path),
+			zap.Int("status", rec.status),
+		)
+	})
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/nightmarlin/aoc2022/aoc"
+)
+
+var authCommand = command{
+	Name:    "auth",
+	Args:    "check",
+	Summary: "check that AoC accepts the session cookie, and show which account it belongs to",
+	Run:     runAuth,
+}
+
+func runAuth(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("auth")
+
+	args, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 || args[0] != "check" {
+		return usageErrorf("expected 'auth check'")
+	}
+
+	fetcher, err := a.fetcher()
+	if err != nil {
+		return err
+	}
+
+	info, err := fetcher.CheckSession(ctx, a.year)
+	if err != nil {
+		return fetchError(err)
+	}
+
+	_, _ = fmt.Fprintf(a.stdout, "session is valid, logged in as %s with %d* in %d\n", info.User, info.Stars, a.year)
+	return nil
+}
+
+// checkSession asks AoC whether it accepts the session cookie, so that an
+// expired session is reported before any time is spent running solutions.
+// Failures other than there being no session or AoC rejecting it are only
+// logged, leaving the requests that follow to report them.
+func (a *app) checkSession(ctx context.Context, fetcher aoc.Fetcher) error {
+	info, err := fetcher.CheckSession(ctx, a.year)
+	switch {
+	case errors.Is(err, aoc.ErrSessionInvalid), errors.Is(err, aoc.ErrNoSession):
+		return fetchError(err)
+	case errors.Is(err, aoc.ErrOffline):
+		// There's nothing to check, and anything missing will be reported.
+	case err != nil:
+		a.log.Warn("unable to check session", zap.Error(err))
+	default:
+		a.log.Debug("session is valid", zap.String("user", info.User))
+	}
+	return nil
+}
+
+// checkSessionForInputs is checkSession for commands that only talk to AoC to
+// fetch inputs, skipping the check when every input is already cached.
+func (a *app) checkSessionForInputs(ctx context.Context, fetcher aoc.Fetcher, puzzles ...aoc.Puzzle) error {
+	for _, p := range puzzles {
+		if !fetcher.IsInputCached(p) {
+			return a.checkSession(ctx, fetcher)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/nightmarlin/aoc2022/aoc"
+	"github.com/nightmarlin/aoc2022/registry"
+)
+
+var benchCommand = command{
+	Name:    "bench",
+	Args:    "<[year/]day|all>",
+	Summary: "run solutions repeatedly and report timing and allocation statistics for each part",
+	Run:     runBench,
+}
+
+// benchStats summarises repeated runs of a single part of a day.
+type benchStats struct {
+	Puzzle     aoc.Puzzle
+	Part, Runs int
+
+	Min, Median, P95 time.Duration
+
+	AllocsPerRun, BytesPerRun uint64
+}
+
+func runBench(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("bench")
+	part := fs.Int("part", 0, "only benchmark the given `part` (1 or 2), rather than both")
+	tag := fs.String("tag", "", "when benchmarking all days, only include those with the `tag`")
+	runs := fs.Int("n", 100, "the `number` of times to run each part")
+
+	args, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return usageErrorf("expected a single day, got %d arguments", len(args))
+	}
+	if *runs < 1 {
+		return usageErrorf("n must be at least 1, got %d", *runs)
+	}
+
+	parts, err := partsToRun(*part)
+	if err != nil {
+		return err
+	}
+
+	entries := entriesWithTag(*tag)
+	if args[0] != "all" {
+		e, err := a.parseSolvedPuzzle(args[0])
+		if err != nil {
+			return err
+		}
+		entries = []registry.Entry{e}
+	}
+
+	fetcher, err := a.fetcher()
+	if err != nil {
+		return err
+	}
+	if err := a.checkSessionForInputs(ctx, fetcher, puzzlesOf(entries)...); err != nil {
+		return err
+	}
+
+	var stats []benchStats
+	for _, entry := range entries {
+		puzzle := entry.Puzzle
+		input, err := fetcher.FetchInput(ctx, puzzle)
+		if err != nil {
+			return inputError(puzzle, err)
+		}
+
+		// Solutions log at debug level while running, which would dominate the
+		// measurements if enabled.
+		solution := entry.New(zap.NewNop())
+
+		for _, p := range parts {
+			a.log.Info("benchmarking", zap.Stringer("puzzle", puzzle), zap.Int("part", p), zap.Int("runs", *runs))
+
+			s, err := benchPart(ctx, solution, p, input, *runs)
+			if err != nil {
+				return withExitCode(exitSolution, fmt.Errorf("%s part %d: %w", puzzle, p, err))
+			}
+			s.Puzzle = puzzle
+			stats = append(stats, s)
+		}
+	}
+
+	printBenchStats(a.stdout, stats)
+	return nil
+}
+
+// benchPart runs a part of a Solution n times, after a single warm-up run, and
+// gathers statistics on how long each run took and how much it allocated.
+func benchPart(ctx context.Context, s registry.Solution, part int, input string, n int) (benchStats, error) {
+	if _, err := runPart(ctx, s, part, input); err != nil {
+		return benchStats{}, err
+	}
+
+	var (
+		durations      = make([]time.Duration, n)
+		before, after  runtime.MemStats
+		allocs, nBytes uint64
+	)
+
+	for i := 0; i < n; i++ {
+		if err := ctx.Err(); err != nil {
+			return benchStats{}, err
+		}
+
+		runtime.ReadMemStats(&before)
+		start := time.Now()
+
+		_, err := runPart(ctx, s, part, input)
+
+		durations[i] = time.Since(start)
+		runtime.ReadMemStats(&after)
+
+		if err != nil {
+			return benchStats{}, err
+		}
+		allocs += after.Mallocs - before.Mallocs
+		nBytes += after.TotalAlloc - before.TotalAlloc
+	}
+
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+
+	return benchStats{
+		Part:         part,
+		Runs:         n,
+		Min:          durations[0],
+		Median:       median(durations),
+		P95:          percentile(durations, 95),
+		AllocsPerRun: allocs / uint64(n),
+		BytesPerRun:  nBytes / uint64(n),
+	}, nil
+}
+
+// median of a sorted, non-empty slice.
+func median(sorted []time.Duration) time.Duration {
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// percentile of a sorted, non-empty slice, using the nearest-rank method.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100 // ceil(p/100 * n)
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func printBenchStats(w io.Writer, stats []benchStats) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', tabwriter.AlignRight)
+	_, _ = fmt.Fprintln(tw, "PUZZLE\tPART\tRUNS\tMIN\tMEDIAN\tP95\tALLOCS/RUN\tBYTES/RUN\t")
+
+	for _, s := range stats {
+		_, _ = fmt.Fprintf(
+			tw,
+			"%s\t%d\t%d\t%s\t%s\t%s\t%d\t%d\t\n",
+			s.Puzzle, s.Part, s.Runs, s.Min, s.Median, s.P95, s.AllocsPerRun, s.BytesPerRun,
+		)
+	}
+	_ = tw.Flush()
+}
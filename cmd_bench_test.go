@@ -0,0 +1,21 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBenchStatistics(t *testing.T) {
+	durations := make([]time.Duration, 20)
+	for i := range durations {
+		durations[i] = time.Duration(i+1) * time.Millisecond
+	}
+
+	assert.Equal(t, 10500*time.Microsecond, median(durations))
+	assert.Equal(t, 19*time.Millisecond, percentile(durations, 95))
+
+	assert.Equal(t, 3*time.Millisecond, median(durations[:5]))
+	assert.Equal(t, time.Millisecond, percentile(durations[:1], 95))
+}
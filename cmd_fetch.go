@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nightmarlin/aoc2022/aoc"
+)
+
+var fetchCommand = command{
+	Name:    "fetch",
+	Args:    "<[year/]day>...",
+	Summary: "download puzzle inputs into the local folder without running anything",
+	Run:     runFetch,
+}
+
+func runFetch(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("fetch")
+	wait := fs.Bool("wait", false, "wait for puzzles to unlock if they haven't yet")
+
+	args, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return usageErrorf("at least one day is required")
+	}
+
+	puzzles := make([]aoc.Puzzle, len(args))
+	for i := range args {
+		if puzzles[i], err = a.parsePuzzle(args[i]); err != nil {
+			return err
+		}
+	}
+
+	fetcher, err := a.fetcher()
+	if err != nil {
+		return err
+	}
+
+	for _, p := range puzzles {
+		if *wait {
+			if err := fetcher.WaitForUnlock(ctx, p); err != nil {
+				return fetchError(err)
+			}
+		}
+
+		input, err := fetcher.FetchInput(ctx, p)
+		if err != nil {
+			return inputError(p, err)
+		}
+		_, _ = fmt.Fprintf(a.stdout, "%s: %d bytes\n", p, len(input))
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"go.uber.org/multierr"
+
+	"github.com/nightmarlin/aoc2022/aoc"
+)
+
+var inputsCommand = command{
+	Name:    "inputs",
+	Args:    "doctor",
+	Summary: "audit the cached inputs, checking each against the metadata recorded when it was fetched",
+	Run:     runInputs,
+}
+
+func runInputs(_ context.Context, a *app, args []string) error {
+	fs := a.flagSet("inputs")
+	fix := fs.Bool("fix", false, "delete invalid inputs so they are fetched again, and record missing metadata")
+
+	args, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 || args[0] != "doctor" {
+		return usageErrorf("expected 'inputs doctor'")
+	}
+
+	fetcher, err := a.localFetcher()
+	if err != nil {
+		return err
+	}
+
+	audits := fetcher.AuditInputs()
+
+	var problems error
+	statuses := make([]string, len(audits))
+	for i, audit := range audits {
+		statuses[i] = inputStatus(audit)
+		if audit.Err == nil {
+			continue
+		}
+
+		if *fix {
+			if err := fetcher.RepairInput(audit); err != nil {
+				statuses[i] += " (repair failed: " + err.Error() + ")"
+			} else {
+				statuses[i] += " (repaired)"
+				continue
+			}
+		}
+		problems = multierr.Append(problems, fmt.Errorf("%s: %w", audit.Puzzle, audit.Err))
+	}
+
+	printInputAudits(a.stdout, audits, statuses)
+
+	if problems != nil && !*fix {
+		problems = fmt.Errorf("%w, run with -fix to repair them", problems)
+	}
+	return withExitCode(exitFailure, problems)
+}
+
+func inputStatus(audit aoc.InputAudit) string {
+	var invalid aoc.InvalidInputError
+	switch {
+	case audit.Err == nil:
+		return "ok"
+	case errors.Is(audit.Err, aoc.ErrNoInputMeta):
+		return "no metadata"
+	case errors.Is(audit.Err, aoc.ErrOrphanedInputMeta):
+		return "input missing"
+	case errors.As(audit.Err, &invalid):
+		return "INVALID: " + invalid.Reason
+	}
+	return "ERROR: " + audit.Err.Error()
+}
+
+func printInputAudits(w io.Writer, audits []aoc.InputAudit, statuses []string) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "PUZZLE\tFETCHED\tLENGTH\tSTATUS")
+
+	for i, audit := range audits {
+		fetched, length := "-", "-"
+		if audit.Meta != nil {
+			fetched = audit.Meta.FetchedAt.Local().Format("2006-01-02 15:04:05")
+			length = fmt.Sprint(audit.Meta.Length)
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", audit.Puzzle, fetched, length, statuses[i])
+	}
+	_ = tw.Flush()
+}
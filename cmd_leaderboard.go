@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/nightmarlin/aoc2022/aoc"
+)
+
+var leaderboardCommand = command{
+	Name:    "leaderboard",
+	Args:    "[id]",
+	Summary: "show a private leaderboard's standings, stars per day and part two times (id defaults to env AOC_LEADERBOARD)",
+	Run:     runLeaderboard,
+}
+
+func runLeaderboard(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("leaderboard")
+	day := fs.Int("day", 0, "show part two times for the `day`, rather than the latest day with any stars")
+
+	args, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	idArg := os.Getenv("AOC_LEADERBOARD")
+	switch {
+	case len(args) == 1:
+		idArg = args[0]
+	case len(args) > 1:
+		return usageErrorf("expected a single leaderboard id, got %d arguments", len(args))
+	case idArg == "":
+		return usageErrorf("please choose a leaderboard, it's the number at the end of its url")
+	}
+
+	id, err := strconv.Atoi(idArg)
+	if err != nil || id < 1 {
+		return usageErrorf("invalid leaderboard id %q", idArg)
+	}
+	if *day < 0 || *day > aoc.DaysPerYear {
+		return usageErrorf("day must be a number between 1 and %d, got %d", aoc.DaysPerYear, *day)
+	}
+
+	fetcher, err := a.fetcher()
+	if err != nil {
+		return err
+	}
+
+	lb, err := fetcher.FetchLeaderboard(ctx, a.year, id)
+	if err != nil {
+		return fetchError(err)
+	}
+
+	standings := lb.Standings()
+	if *day == 0 {
+		*day = lb.LastDay()
+	}
+
+	w := a.stdout
+	_, _ = fmt.Fprintf(w, "leaderboard %d, %d, as of %s\n\n", id, a.year, lb.FetchedAt.Local().Format("2006-01-02 15:04:05"))
+	printStandings(w, standings, lb.LastDay())
+
+	if *day > 0 {
+		_, _ = fmt.Fprintln(w)
+		printDeltas(w, aoc.Puzzle{Year: a.year, Day: *day}, standings)
+	}
+	return nil
+}
+
+// printStandings shows each member's rank, score and stars, along with a row
+// of stars per day in the style of AoC's own leaderboard: '*' for both parts,
+// '+' for part one only and '.' for neither.
+func printStandings(w io.Writer, standings []aoc.LeaderboardMember, days int) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	var tens, units strings.Builder
+	for d := 1; d <= days; d++ {
+		tens.WriteString(dayDigit(d / 10))
+		units.WriteString(strconv.Itoa(d % 10))
+	}
+	_, _ = fmt.Fprintf(tw, "\t\t\t\t%s\n", tens.String())
+	_, _ = fmt.Fprintf(tw, "RANK\tNAME\tSCORE\tSTARS\t%s\n", units.String())
+
+	for i, m := range standings {
+		var stars strings.Builder
+		for d := 1; d <= days; d++ {
+			_, one := m.StarAt(d, 1)
+			_, two := m.StarAt(d, 2)
+			switch {
+			case two:
+				stars.WriteString("*")
+			case one:
+				stars.WriteString("+")
+			default:
+				stars.WriteString(".")
+			}
+		}
+		_, _ = fmt.Fprintf(tw, "%d\t%s\t%d\t%d\t%s\n", i+1, m.DisplayName(), m.LocalScore, m.Stars, stars.String())
+	}
+	_ = tw.Flush()
+}
+
+func dayDigit(d int) string {
+	if d == 0 {
+		return " "
+	}
+	return strconv.Itoa(d)
+}
+
+// printDeltas shows how long after the puzzle unlocked each member solved each
+// part, and the time from part one to part two, fastest part two first.
+func printDeltas(w io.Writer, p aoc.Puzzle, standings []aoc.LeaderboardMember) {
+	type row struct {
+		name     string
+		one, two string
+		delta    time.Duration
+		hasDelta bool
+	}
+
+	var rows []row
+	for _, m := range standings {
+		one, ok := m.StarAt(p.Day, 1)
+		if !ok {
+			continue
+		}
+
+		r := row{name: m.DisplayName(), one: formatSince(one, p), two: "-"}
+		if two, ok := m.StarAt(p.Day, 2); ok {
+			r.two = formatSince(two, p)
+		}
+		r.delta, r.hasDelta = m.Delta(p.Day)
+		rows = append(rows, r)
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].hasDelta != rows[j].hasDelta {
+			return rows[i].hasDelta
+		}
+		return rows[i].delta < rows[j].delta
+	})
+
+	_, _ = fmt.Fprintf(w, "%s part two times:\n", p)
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "NAME\tPART 1\tPART 2\tDELTA")
+	for _, r := range rows {
+		delta := "-"
+		if r.hasDelta {
+			delta = r.delta.String()
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", r.name, r.one, r.two, delta)
+	}
+	_ = tw.Flush()
+}
+
+// formatSince formats how long after the puzzle unlocked t was.
+func formatSince(t time.Time, p aoc.Puzzle) string {
+	return t.Sub(p.UnlockTime()).Round(time.Second).String()
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+)
+
+var listCommand = command{
+	Name:    "list",
+	Args:    "",
+	Summary: "list the days that have a solution, with their titles, tags and expected complexity",
+	Run:     runList,
+}
+
+func runList(_ context.Context, a *app, args []string) error {
+	fs := a.flagSet("list")
+	tag := fs.String("tag", "", "only list days with the `tag`")
+
+	args, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 0 {
+		return usageErrorf("list takes no arguments")
+	}
+
+	tw := tabwriter.NewWriter(a.stdout, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "PUZZLE\tTITLE\tTAGS\tCOMPLEXITY")
+
+	for _, e := range entriesWithTag(*tag) {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", e.Puzzle, e.Title, strings.Join(e.Tags, ", "), e.Complexity)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+var readCommand = command{
+	Name:    "read",
+	Args:    "<[year/]day>",
+	Summary: "show the puzzle description as Markdown, including part two once it's unlocked",
+	Run:     runRead,
+}
+
+func runRead(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("read")
+	wait := fs.Bool("wait", false, "wait for the puzzle to unlock if it hasn't yet")
+
+	args, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return usageErrorf("expected a single day, got %d arguments", len(args))
+	}
+
+	puzzle, err := a.parsePuzzle(args[0])
+	if err != nil {
+		return err
+	}
+
+	fetcher, err := a.fetcher()
+	if err != nil {
+		return err
+	}
+
+	if *wait {
+		if err := fetcher.WaitForUnlock(ctx, puzzle); err != nil {
+			return fetchError(err)
+		}
+	}
+
+	desc, err := fetcher.FetchDescription(ctx, puzzle)
+	if err != nil {
+		return fetchError(fmt.Errorf("unable to get description for %s: %w", puzzle, err))
+	}
+
+	_, _ = fmt.Fprint(a.stdout, desc)
+	return nil
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
+	"github.com/nightmarlin/aoc2022/aoc"
+	"github.com/nightmarlin/aoc2022/lib"
+	"github.com/nightmarlin/aoc2022/registry"
+)
+
+var runCommand = command{
+	Name:    "run",
+	Args:    "<[year/]day|all>",
+	Summary: "run a solution against its puzzle input, fetching the input if needed (day defaults to env SOLUTION)",
+	Run:     runRun,
+}
+
+func runRun(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("run")
+	part := fs.Int("part", 0, "only run the given `part` (1 or 2), rather than both")
+	parallel := fs.Int("parallel", 1, "when running all days, run up to `n` days at once")
+	example := fs.Bool("example", false, "run against the worked examples from the puzzle page, checking their answers")
+	tag := fs.String("tag", "", "when running all days, only run those with the `tag`")
+	wait := fs.Bool("wait", false, "wait for the puzzle to unlock if it hasn't yet")
+	accounts := fs.Bool("accounts", false, "run against every account's input, showing the answers side by side")
+	inputSource := fs.String("input", "", "run against the `source` rather than the puzzle input: a file, - for stdin, or a directory of inputs")
+
+	args, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	dayArg := os.Getenv("SOLUTION")
+	switch {
+	case len(args) == 1:
+		dayArg = args[0]
+	case len(args) > 1:
+		return usageErrorf("expected a single day, got %d arguments", len(args))
+	case dayArg == "":
+		return usageErrorf("please choose a solution to run, see the list command for available days")
+	}
+
+	parts, err := partsToRun(*part)
+	if err != nil {
+		return err
+	}
+
+	if dayArg == "all" {
+		if *example {
+			return usageErrorf("examples can only be run for a single day")
+		}
+		if *accounts {
+			return usageErrorf("accounts can only be compared for a single day")
+		}
+		if *inputSource != "" {
+			return usageErrorf("-input can only be used with a single day")
+		}
+		return runAll(ctx, a, entriesWithTag(*tag), parts, *parallel)
+	}
+
+	entry, err := a.parseSolvedPuzzle(dayArg)
+	if err != nil {
+		return err
+	}
+	puzzle := entry.Puzzle
+
+	if *inputSource != "" {
+		if *example || *accounts {
+			return usageErrorf("-input can't be used with -example or -accounts")
+		}
+		return runCustomInputs(ctx, a, entry, parts, *inputSource)
+	}
+
+	if *accounts {
+		if *example {
+			return usageErrorf("-example and -accounts can't be used together")
+		}
+		return runAccounts(ctx, a, entry, parts)
+	}
+
+	fetcher, err := a.fetcher()
+	if err != nil {
+		return err
+	}
+
+	if *example {
+		return runExamples(ctx, a, fetcher, puzzle, entry.New(a.log), parts)
+	}
+
+	if *wait {
+		if err := fetcher.WaitForUnlock(ctx, puzzle); err != nil {
+			return fetchError(err)
+		}
+	}
+
+	if err := a.checkSessionForInputs(ctx, fetcher, puzzle); err != nil {
+		return err
+	}
+
+	input, err := fetcher.FetchInput(ctx, puzzle)
+	if err != nil {
+		return inputError(puzzle, err)
+	}
+
+	log := a.log.With(zap.Stringer("puzzle", puzzle))
+	log.Info("input fetched, initializing solution")
+	solution := entry.New(a.log)
+
+	for _, p := range parts {
+		log.Info("running solution", zap.Int("part", p))
+
+		answer, err := runPart(ctx, solution, p, input)
+		if err != nil {
+			return withExitCode(exitSolution, fmt.Errorf("error occurred while running part %d: %w", p, err))
+		}
+		printAnswer(a, p, answer)
+	}
+
+	log.Info("complete!")
+	return nil
+}
+
+// runExamples runs the chosen parts of the Solution against each of the day's
+// worked examples, and fails if any answer differs from the expected one.
+func runExamples(
+	ctx context.Context,
+	a *app,
+	fetcher aoc.Fetcher,
+	puzzle aoc.Puzzle,
+	solution registry.Solution,
+	parts []int,
+) error {
+	examples, err := fetcher.FetchExamples(ctx, puzzle)
+	if err != nil {
+		return fetchError(fmt.Errorf("unable to get examples for %s: %w", puzzle, err))
+	}
+
+	var mismatches error
+	for i, e := range examples {
+		if !containsInt(parts, e.Part) {
+			continue
+		}
+
+		answer, err := runPart(ctx, solution, e.Part, e.Input)
+		if err != nil {
+			return withExitCode(exitSolution, fmt.Errorf("error occurred while running example %d: %w", i+1, err))
+		}
+
+		status := "ok"
+		if !answer.Equal(e.Answer) {
+			status = "MISMATCH"
+			mismatches = multierr.Append(
+				mismatches,
+				fmt.Errorf("example %d part %d: got %s, want %s", i+1, e.Part, answer, e.Answer),
+			)
+		}
+		_, _ = fmt.Fprintf(a.stdout, "example %d part %d: %s (want %s) %s\n", i+1, e.Part, answer, e.Answer, status)
+	}
+	return withExitCode(exitMismatch, mismatches)
+}
+
+func containsInt(s []int, i int) bool {
+	return lib.Any(s, func(v int) bool { return v == i })
+}
+
+// partsToRun converts the value of a -part flag into the parts to run, where 0
+// means both.
+func partsToRun(part int) ([]int, error) {
+	switch part {
+	case 0:
+		return []int{1, 2}, nil
+	case 1, 2:
+		return []int{part}, nil
+	}
+	return nil, usageErrorf("part must be 1 or 2, got %d", part)
+}
+
+// printAnswer writes the answer to stdout, keeping it separate from the logs
+// (which go to stderr) so that it can be piped elsewhere.
+func printAnswer(a *app, part int, answer aoc.Answer) {
+	_, _ = fmt.Fprintf(a.stdout, "part %d: %s\n", part, answer)
+}
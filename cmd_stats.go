@@ -0,0 +1,254 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/nightmarlin/aoc2022/aoc"
+	"github.com/nightmarlin/aoc2022/registry"
+)
+
+var statsCommand = command{
+	Name:    "stats",
+	Args:    "",
+	Summary: "show your personal times and ranks for each day, alongside how long the solutions take to run",
+	Run:     runStats,
+}
+
+// recentDays is how many of the latest days are compared against the rest to
+// show a trend.
+const recentDays = 5
+
+func runStats(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("stats")
+	run := fs.Bool("run", true, "time the solutions for days whose input is cached")
+
+	args, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) != 0 {
+		return usageErrorf("stats takes no arguments")
+	}
+
+	fetcher, err := a.fetcher()
+	if err != nil {
+		return err
+	}
+
+	stats, err := fetcher.FetchPersonalStats(ctx, a.year)
+	if err != nil {
+		return fetchError(err)
+	}
+	if len(stats.Days) == 0 {
+		_, _ = fmt.Fprintf(a.stdout, "no stars collected in %d yet\n", a.year)
+		return nil
+	}
+
+	// Runtimes are keyed by day, then part.
+	runtimes := map[int]map[int]partResult{}
+	if *run {
+		for _, d := range stats.Days {
+			entry, ok := registry.Lookup(aoc.Puzzle{Year: a.year, Day: d.Day})
+			if !ok || !fetcher.IsInputCached(entry.Puzzle) {
+				continue
+			}
+
+			results, _ := runPuzzle(ctx, a.log, fetcher, entry, []int{1, 2})
+			runtimes[d.Day] = map[int]partResult{}
+			for _, r := range results {
+				runtimes[d.Day][r.Part] = r
+			}
+		}
+	}
+
+	_, _ = fmt.Fprintf(a.stdout, "personal stats for %d, as of %s\n\n", a.year, stats.FetchedAt.Local().Format("2006-01-02 15:04:05"))
+	printDayStats(a.stdout, stats, runtimes)
+	_, _ = fmt.Fprintln(a.stdout)
+	printTrends(a.stdout, stats)
+
+	history, err := fetcher.PersonalStatsHistory(a.year)
+	switch {
+	case err != nil:
+		a.log.Warn("failed to load stats history", zap.Error(err))
+	case len(history) >= 2:
+		_, _ = fmt.Fprintln(a.stdout)
+		printStatsChanges(a.stdout, history[len(history)-2], history[len(history)-1])
+	}
+	return nil
+}
+
+func printDayStats(w io.Writer, stats aoc.PersonalStats, runtimes map[int]map[int]partResult) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "DAY\tPART 1\tRANK\tPART 2\tRANK\tDELTA\tRUNTIME 1\tRUNTIME 2")
+
+	for _, d := range stats.Days {
+		delta := "-"
+		if d.PartOne != nil && d.PartTwo != nil && !d.PartOne.OverADay && !d.PartTwo.OverADay {
+			delta = (d.PartTwo.Time - d.PartOne.Time).String()
+		}
+
+		_, _ = fmt.Fprintf(
+			tw,
+			"%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			d.Day,
+			formatPartTime(d.PartOne), formatPartRank(d.PartOne),
+			formatPartTime(d.PartTwo), formatPartRank(d.PartTwo),
+			delta,
+			formatRuntime(runtimes[d.Day], 1), formatRuntime(runtimes[d.Day], 2),
+		)
+	}
+	_ = tw.Flush()
+}
+
+// printTrends summarises each part, comparing the median rank of the latest
+// days with that of the days before them.
+func printTrends(w io.Writer, stats aoc.PersonalStats) {
+	for part := 1; part <= 2; part++ {
+		var (
+			ranks    []int
+			times    []time.Duration
+			best     *aoc.PartStats
+			bestDays []int
+		)
+		for _, d := range stats.Days {
+			ps := d.Part(part)
+			if ps == nil {
+				continue
+			}
+
+			ranks = append(ranks, ps.Rank)
+			if !ps.OverADay {
+				times = append(times, ps.Time)
+			}
+			switch {
+			case best == nil || ps.Rank < best.Rank:
+				best, bestDays = ps, []int{d.Day}
+			case ps.Rank == best.Rank:
+				bestDays = append(bestDays, d.Day)
+			}
+		}
+
+		if len(ranks) == 0 {
+			_, _ = fmt.Fprintf(w, "part %d: not solved yet\n", part)
+			continue
+		}
+
+		_, _ = fmt.Fprintf(
+			w,
+			"part %d: %d solved, median rank %d, best rank %d (day %s)",
+			part, len(ranks), medianInt(ranks), best.Rank, joinInts(bestDays),
+		)
+		if len(times) > 0 {
+			sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+			_, _ = fmt.Fprintf(w, ", median time %s", median(times))
+		}
+		_, _ = fmt.Fprintln(w)
+
+		if len(ranks) > recentDays {
+			recent, earlier := medianInt(ranks[len(ranks)-recentDays:]), medianInt(ranks[:len(ranks)-recentDays])
+			trend := "steady"
+			switch {
+			case recent < earlier:
+				trend = "improving"
+			case recent > earlier:
+				trend = "slipping"
+			}
+			_, _ = fmt.Fprintf(
+				w,
+				"        last %d days median rank %d, against %d before - %s\n",
+				recentDays, recent, earlier, trend,
+			)
+		}
+	}
+}
+
+// printStatsChanges lists the parts whose time or rank differs between two
+// snapshots of the stats, which includes any solved in between.
+func printStatsChanges(w io.Writer, before, after aoc.PersonalStats) {
+	_, _ = fmt.Fprintf(w, "changes since %s:\n", before.FetchedAt.Local().Format("2006-01-02 15:04:05"))
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "DAY\tPART\tTIME\tRANK")
+
+	changed := false
+	for _, d := range after.Days {
+		prev, _ := before.Day(d.Day)
+		for part := 1; part <= 2; part++ {
+			was, now := prev.Part(part), d.Part(part)
+			if now == nil || (was != nil && *was == *now) {
+				continue
+			}
+
+			changed = true
+			_, _ = fmt.Fprintf(
+				tw,
+				"%d\t%d\t%s -> %s\t%s -> %s\n",
+				d.Day, part,
+				formatPartTime(was), formatPartTime(now),
+				formatPartRank(was), formatPartRank(now),
+			)
+		}
+	}
+
+	if !changed {
+		_, _ = fmt.Fprintln(w, "no times or ranks have changed")
+		return
+	}
+	_ = tw.Flush()
+}
+
+func formatPartTime(ps *aoc.PartStats) string {
+	switch {
+	case ps == nil:
+		return "-"
+	case ps.OverADay:
+		return ">24h"
+	}
+	return fmt.Sprintf(
+		"%02d:%02d:%02d",
+		int(ps.Time.Hours()), int(ps.Time.Minutes())%60, int(ps.Time.Seconds())%60,
+	)
+}
+
+func formatPartRank(ps *aoc.PartStats) string {
+	if ps == nil {
+		return "-"
+	}
+	return strconv.Itoa(ps.Rank)
+}
+
+func formatRuntime(results map[int]partResult, part int) string {
+	r, ok := results[part]
+	switch {
+	case !ok:
+		return "-"
+	case r.Err != nil:
+		return "failed"
+	}
+	return r.Duration.Round(time.Microsecond).String()
+}
+
+func medianInt(s []int) int {
+	sorted := append([]int(nil), s...)
+	sort.Ints(sorted)
+	return sorted[len(sorted)/2]
+}
+
+func joinInts(s []int) string {
+	res := ""
+	for i, v := range s {
+		if i > 0 {
+			res += ", "
+		}
+		res += strconv.Itoa(v)
+	}
+	return res
+}
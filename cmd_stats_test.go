@@ -0,0 +1,42 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nightmarlin/aoc2022/aoc"
+)
+
+func TestPrintStatsChanges(t *testing.T) {
+	before := aoc.PersonalStats{
+		Year: 2022,
+		Days: []aoc.DayStats{
+			{Day: 1, PartOne: &aoc.PartStats{Time: time.Minute, Rank: 100}, PartTwo: &aoc.PartStats{Time: time.Hour, Rank: 900}},
+			{Day: 2, PartOne: &aoc.PartStats{OverADay: true, Rank: 5000}},
+		},
+	}
+	after := aoc.PersonalStats{
+		Year: 2022,
+		Days: []aoc.DayStats{
+			before.Days[0],
+			{Day: 2, PartOne: before.Days[1].PartOne, PartTwo: &aoc.PartStats{OverADay: true, Rank: 4000}},
+			{Day: 3, PartOne: &aoc.PartStats{Time: 90 * time.Second, Rank: 42}},
+		},
+	}
+
+	var buf bytes.Buffer
+	printStatsChanges(&buf, before, after)
+	out := buf.String()
+
+	assert.NotRegexp(t, `(?m)^1\s`, out, "day 1 hasn't changed")
+	assert.NotRegexp(t, `(?m)^2\s+1\s`, out, "day 2 part 1 hasn't changed")
+	assert.Regexp(t, `(?m)^2\s+2\s+- -> >24h\s+- -> 4000$`, out)
+	assert.Regexp(t, `(?m)^3\s+1\s+- -> 00:01:30\s+- -> 42$`, out)
+
+	buf.Reset()
+	printStatsChanges(&buf, after, after)
+	assert.Contains(t, buf.String(), "no times or ranks have changed")
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"github.com/nightmarlin/aoc2022/aoc"
+	"github.com/nightmarlin/aoc2022/registry"
+)
+
+var submitCommand = command{
+	Name:    "submit",
+	Args:    "<[year/]day> [answer]",
+	Summary: "submit an answer to AoC, running the solution to find it if one isn't given",
+	Run:     runSubmit,
+}
+
+func runSubmit(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("submit")
+	part := fs.Int("part", 1, "the `part` (1 or 2) to submit an answer for")
+
+	args, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(args) < 1 || len(args) > 2 {
+		return usageErrorf("expected a day and optionally an answer, got %d arguments", len(args))
+	}
+	if *part != 1 && *part != 2 {
+		return usageErrorf("part must be 1 or 2, got %d", *part)
+	}
+
+	puzzle, err := a.parsePuzzle(args[0])
+	if err != nil {
+		return err
+	}
+
+	fetcher, err := a.fetcher()
+	if err != nil {
+		return err
+	}
+	// Submitting always needs a valid session, so there's no point running the
+	// solution without one.
+	if err := a.checkSession(ctx, fetcher); err != nil {
+		return err
+	}
+
+	var answer aoc.Answer
+	if len(args) == 2 {
+		answer = aoc.ParseAnswer(args[1])
+	} else {
+		entry, ok := registry.Lookup(puzzle)
+		if !ok {
+			return usageErrorf("the solution for %s has not been completed, so an answer must be given", puzzle)
+		}
+
+		results, err := runPuzzle(ctx, a.log, fetcher, entry, []int{*part})
+		switch {
+		case err != nil && isFetchError(err):
+			return err
+		case err != nil:
+			return withExitCode(exitSolution, err)
+		}
+		answer = results[0].Answer
+	}
+
+	a.log.Info("submitting answer", zap.Stringer("puzzle", puzzle), zap.Int("part", *part), zap.Stringer("answer", answer))
+
+	res, err := fetcher.Submit(ctx, puzzle, *part, answer)
+	switch {
+	case errors.Is(err, aoc.ErrKnownWrong), errors.Is(err, aoc.ErrOutOfBounds), errors.Is(err, aoc.ErrAlreadySolved):
+		return withExitCode(exitRejected, err)
+	case errors.Is(err, aoc.ErrSessionInvalid):
+		return withExitCode(exitConfig, err)
+	case err != nil:
+		return fetchError(fmt.Errorf("failed to submit answer: %w", err))
+	}
+
+	_, _ = fmt.Fprintf(a.stdout, "%s part %d: %s is %s\n%s\n", puzzle, *part, answer, res.Verdict, res.Message)
+
+	if res.Verdict.IsWrong() || res.Verdict == aoc.VerdictRateLimited {
+		if res.Wait > 0 {
+			return withExitCode(exitRejected, fmt.Errorf("answer was %s, wait %s before trying again", res.Verdict, res.Wait))
+		}
+		return withExitCode(exitRejected, fmt.Errorf("answer was %s", res.Verdict))
+	}
+	return nil
+}
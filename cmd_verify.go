@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
+	"github.com/nightmarlin/aoc2022/registry"
+)
+
+var verifyCommand = command{
+	Name:    "verify",
+	Args:    "[[year/]day...]",
+	Summary: "re-run solutions and check their answers against the golden answers file",
+	Run:     runVerify,
+}
+
+// A verifyResult is a partResult alongside the answer it was expected to have.
+type verifyResult struct {
+	partResult
+
+	Golden    string
+	HasGolden bool
+}
+
+func (r verifyResult) Status() string {
+	switch {
+	case r.Err != nil:
+		return "failed"
+	case !r.HasGolden:
+		return "unverified"
+	case r.Golden != r.Answer.String():
+		return "MISMATCH"
+	}
+	return "ok"
+}
+
+func runVerify(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("verify")
+	answersPath := fs.String("answers", defaultGoldenFile, "the golden answers `file`")
+	update := fs.Bool("update", false, "record the current answers as golden, rather than checking them")
+	tag := fs.String("tag", "", "when no days are given, only verify those with the `tag`")
+
+	args, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	entries := entriesWithTag(*tag)
+	if len(args) > 0 {
+		entries = make([]registry.Entry, len(args))
+		for i := range args {
+			if entries[i], err = a.parseSolvedPuzzle(args[i]); err != nil {
+				return err
+			}
+		}
+	}
+
+	golden, err := loadGoldenAnswers(*answersPath)
+	if err != nil {
+		return withExitCode(exitConfig, err)
+	}
+
+	fetcher, err := a.fetcher()
+	if err != nil {
+		return err
+	}
+	if err := a.checkSessionForInputs(ctx, fetcher, puzzlesOf(entries)...); err != nil {
+		return err
+	}
+
+	var (
+		results []verifyResult
+		errs    error
+	)
+	for _, e := range entries {
+		puzzleResults, err := runPuzzle(ctx, a.log, fetcher, e, []int{1, 2})
+		errs = multierr.Append(errs, err)
+
+		for _, r := range puzzleResults {
+			vr := verifyResult{partResult: r}
+			if g, ok := golden.Get(r.Puzzle, r.Part); ok {
+				vr.Golden, vr.HasGolden = g.String(), true
+			}
+			results = append(results, vr)
+		}
+	}
+
+	if *update {
+		for _, r := range results {
+			if r.Err == nil {
+				golden.Set(r.Puzzle, r.Part, r.Answer)
+			}
+		}
+		if err := golden.Save(*answersPath); err != nil {
+			return withExitCode(exitFailure, err)
+		}
+		a.log.Info("golden answers updated", zap.String("file", *answersPath))
+	}
+
+	printVerifyResults(a.stdout, results)
+
+	switch {
+	case errs != nil && isFetchError(errs):
+		return fetchError(errs)
+	case errs != nil:
+		return withExitCode(exitSolution, errs)
+	}
+
+	if *update {
+		return nil
+	}
+
+	var mismatches error
+	for _, r := range results {
+		if r.Status() == "MISMATCH" {
+			mismatches = multierr.Append(
+				mismatches,
+				fmt.Errorf("%s part %d: got %s, want %s", r.Puzzle, r.Part, r.Answer, r.Golden),
+			)
+		}
+	}
+	return withExitCode(exitMismatch, mismatches)
+}
+
+func printVerifyResults(w io.Writer, results []verifyResult) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "PUZZLE\tPART\tANSWER\tGOLDEN\tDURATION\tSTATUS")
+
+	for _, r := range results {
+		_, _ = fmt.Fprintf(
+			tw,
+			"%s\t%d\t%s\t%s\t%s\t%s\n",
+			r.Puzzle, r.Part, r.Answer, r.Golden, r.Duration.Round(time.Microsecond), r.Status(),
+		)
+	}
+	_ = tw.Flush()
+}
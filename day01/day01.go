@@ -2,13 +2,16 @@ package day01
 
 import (
 	"context"
+	"io"
 	"sort"
 	"strconv"
 	"strings"
 
 	"go.uber.org/zap"
 
+	"github.com/nightmarlin/aoc2022/aoc"
 	"github.com/nightmarlin/aoc2022/lib"
+	"github.com/nightmarlin/aoc2022/registry"
 )
 
 // Day01 is a challenge focused on basic string parsing. It can be solved using
@@ -17,6 +20,18 @@ type Day01 struct {
 	log *zap.Logger
 }
 
+var _ registry.StreamingSolution = Day01{}
+
+func init() {
+	registry.Register(registry.Entry{
+		Puzzle:     aoc.Puzzle{Year: 2022, Day: 1},
+		Title:      "Calorie Counting",
+		Tags:       []string{registry.TagParsing, registry.TagSorting},
+		Complexity: "O(n)",
+		New:        func(log *zap.Logger) registry.Solution { return New(log) },
+	})
+}
+
 func New(log *zap.Logger) Day01 {
 	return Day01{log: log.Named("day-01")}
 }
@@ -36,29 +51,45 @@ func (d Day01) ParseLineValues(valueStr string) int {
 	return int(val)
 }
 
-// SumEachGroup splits the input string into groups (two consecutive newlines),
-// maps the lines in each group to a number (using ParseLineValues), and then
-// sums (reduces) the lines in each group to a single value.
-func (d Day01) SumEachGroup(input string) []int {
-	return lib.Map(
-		strings.Split(input, "\n\n"), // Each elf is split by two lines
-		func(group string) int {
-			return lib.Reduce(
-				lib.Map(
-					strings.Split(group, "\n"), // Each calorie count is on its own line
-					d.ParseLineValues,
-				),
-				func(prev, next int) int { return prev + next }, // Sum total calories for each elf
-				0,
-			)
-		},
+// TopGroupSums reads the input a line at a time, summing the lines in each
+// group (separated by a blank line) using ParseLineValues. Only the n largest
+// sums are kept, which are returned from high to low, so memory use doesn't
+// depend on the number of groups.
+func (d Day01) TopGroupSums(ctx context.Context, r io.Reader, n int) ([]int, error) {
+	var (
+		top   = make([]int, 0, n)
+		group int
 	)
+
+	err := lib.ScanLines(ctx, r, func(line string) error {
+		if line == "" { // Each elf is split by a blank line
+			top = keepTopN(top, group, n)
+			group = 0
+			return nil
+		}
+		group += d.ParseLineValues(line) // Each calorie count is on its own line
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return keepTopN(top, group, n), nil
 }
 
-// SortDesc sorts the input slice from high to low.
-func (d Day01) SortDesc(groupSums []int) []int {
-	sort.Slice(groupSums, func(i, j int) bool { return groupSums[i] > groupSums[j] })
-	return groupSums
+// keepTopN inserts v into top, which is sorted from high to low, keeping at
+// most n values.
+func keepTopN(top []int, v, n int) []int {
+	i := sort.Search(len(top), func(i int) bool { return top[i] < v })
+	if i >= n {
+		return top
+	}
+
+	if len(top) < n {
+		top = append(top, 0)
+	}
+	copy(top[i+1:], top[i:len(top)-1])
+	top[i] = v
+	return top
 }
 
 // SumTopN slices the first n values of a sorted slice and sums them together.
@@ -90,24 +121,42 @@ func (d Day01) SumTopN(sortedGroupSums []int, n int) int {
 // Where each line with a value represents the calorie count for an item held by
 // that Elf, and each grouping of items represents the set of items held by that
 // Elf.
-func (d Day01) PartOne(_ context.Context, input string) error {
-	mostCalories := d.SumTopN(d.SortDesc(d.SumEachGroup(input)), 1)
+func (d Day01) PartOne(ctx context.Context, input string) (aoc.Answer, error) {
+	return d.PartOneReader(ctx, strings.NewReader(input))
+}
+
+// PartOneReader is PartOne, reading the input as it goes.
+func (d Day01) PartOneReader(ctx context.Context, r io.Reader) (aoc.Answer, error) {
+	top, err := d.TopGroupSums(ctx, r, 1)
+	if err != nil {
+		return aoc.Answer{}, err
+	}
+	mostCalories := d.SumTopN(top, 1)
 
-	d.log.Info(
+	d.log.Debug(
 		"maximum calorie count found",
 		zap.Int("calories", mostCalories),
 	)
-	return nil
+	return aoc.IntAnswer(mostCalories), nil
 }
 
 // PartTwo asks a similar question, but in the spirit of fairness asks the total
 // number of calories shared between the three Elves carrying the most calories.
-func (d Day01) PartTwo(_ context.Context, input string) error {
-	topThreeSum := d.SumTopN(d.SortDesc(d.SumEachGroup(input)), 3)
+func (d Day01) PartTwo(ctx context.Context, input string) (aoc.Answer, error) {
+	return d.PartTwoReader(ctx, strings.NewReader(input))
+}
+
+// PartTwoReader is PartTwo, reading the input as it goes.
+func (d Day01) PartTwoReader(ctx context.Context, r io.Reader) (aoc.Answer, error) {
+	top, err := d.TopGroupSums(ctx, r, 3)
+	if err != nil {
+		return aoc.Answer{}, err
+	}
+	topThreeSum := d.SumTopN(top, 3)
 
-	d.log.Info(
+	d.log.Debug(
 		"sum of calories for 3 elves holding most calories found",
 		zap.Int("calories-sum", topThreeSum),
 	)
-	return nil
+	return aoc.IntAnswer(topThreeSum), nil
 }
@@ -0,0 +1,98 @@
+package day01
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/nightmarlin/aoc2022/aoc"
+	"github.com/nightmarlin/aoc2022/aoc/aoctest"
+)
+
+// exampleInput is the worked example from the puzzle description.
+const exampleInput = `1000
+2000
+3000
+
+4000
+
+5000
+6000
+
+7000
+8000
+9000
+
+10000
+`
+
+func TestExample(t *testing.T) {
+	var (
+		ctx = context.Background()
+		d   = New(zap.NewNop())
+	)
+
+	partOne, err := d.PartOne(ctx, exampleInput)
+	require.NoError(t, err)
+	assert.Equal(t, "24000", partOne.String())
+
+	partTwo, err := d.PartTwo(ctx, exampleInput)
+	require.NoError(t, err)
+	assert.Equal(t, "45000", partTwo.String())
+}
+
+// streamRepeats is how many copies of the example TestStreaming reads.
+const streamRepeats = 10_000
+
+// TestStreaming runs the example many times over, separating each copy with a
+// blank line so that its groups stay apart. Only the largest groups count, so
+// the answers don't grow with the input.
+func TestStreaming(t *testing.T) {
+	var (
+		ctx = context.Background()
+		d   = New(zap.NewNop())
+	)
+
+	partOne, err := d.PartOneReader(ctx, aoctest.RepeatReader(exampleInput+"\n", streamRepeats))
+	require.NoError(t, err)
+	assert.Equal(t, aoc.IntAnswer(24000), partOne)
+
+	partTwo, err := d.PartTwoReader(ctx, aoctest.RepeatReader(exampleInput+"\n", streamRepeats))
+	require.NoError(t, err)
+	assert.Equal(t, aoc.IntAnswer(72000), partTwo)
+}
+
+func BenchmarkPartOne(b *testing.B) {
+	var (
+		ctx   = context.Background()
+		input = aoctest.Input(b, aoc.Puzzle{Year: 2022, Day: 1})
+		d     = New(zap.NewNop())
+	)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := d.PartOne(ctx, input); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPartTwo(b *testing.B) {
+	var (
+		ctx   = context.Background()
+		input = aoctest.Input(b, aoc.Puzzle{Year: 2022, Day: 1})
+		d     = New(zap.NewNop())
+	)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := d.PartTwo(ctx, input); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -3,17 +3,32 @@ package day02
 import (
 	"context"
 	"fmt"
+	"io"
 	"strings"
 
 	"go.uber.org/zap"
 
+	"github.com/nightmarlin/aoc2022/aoc"
 	"github.com/nightmarlin/aoc2022/lib"
+	"github.com/nightmarlin/aoc2022/registry"
 )
 
 type Day02 struct {
 	log *zap.Logger
 }
 
+var _ registry.StreamingSolution = Day02{}
+
+func init() {
+	registry.Register(registry.Entry{
+		Puzzle:     aoc.Puzzle{Year: 2022, Day: 2},
+		Title:      "Rock Paper Scissors",
+		Tags:       []string{registry.TagParsing, registry.TagSimulation},
+		Complexity: "O(n)",
+		New:        func(log *zap.Logger) registry.Solution { return New(log) },
+	})
+}
+
 func New(log *zap.Logger) Day02 {
 	return Day02{log: log.Named("day-02")}
 }
@@ -146,23 +161,18 @@ func Round(theirs, yours RPS) int {
 	return yours.VS(theirs).Score() + yours.Value()
 }
 
-// RunGame takes the puzzle input string, splits it into lines, and passes each
-// line to the lineHandler to calculate the score. It then sums the resulting
-// scores and returns that value.
-func RunGame(input string, lineHandler func(theirCh, yourCh uint8) int) int {
-	return lib.Reduce(
-		lib.Map(
-			strings.Split(input, "\n"),
-			func(line string) int {
-				if len(line) != 3 {
-					return 0
-				}
-				return lineHandler(line[0], line[2])
-			},
-		),
-		func(prev, next int) int { return prev + next },
-		0,
-	)
+// RunGame reads the puzzle input a line at a time, passing each line to the
+// lineHandler to calculate the score for that round. It returns the sum of the
+// scores.
+func RunGame(ctx context.Context, r io.Reader, lineHandler func(theirCh, yourCh uint8) int) (int, error) {
+	total := 0
+	err := lib.ScanLines(ctx, r, func(line string) error {
+		if len(line) == 3 {
+			total += lineHandler(line[0], line[2])
+		}
+		return nil
+	})
+	return total, err
 }
 
 // PartOne presumes that the input is a guide to which RPS to play each round -
@@ -182,17 +192,26 @@ func RunGame(input string, lineHandler func(theirCh, yourCh uint8) int) int {
 // defined above.
 //
 // Calculate the score from the given input using the above rules.
-func (d Day02) PartOne(_ context.Context, input string) error {
-	totalScore := RunGame(
-		input,
+func (d Day02) PartOne(ctx context.Context, input string) (aoc.Answer, error) {
+	return d.PartOneReader(ctx, strings.NewReader(input))
+}
+
+// PartOneReader is PartOne, reading the input as it goes.
+func (d Day02) PartOneReader(ctx context.Context, r io.Reader) (aoc.Answer, error) {
+	totalScore, err := RunGame(
+		ctx,
+		r,
 		func(theirCh, yourCh uint8) (roundScore int) {
 			return Round(ToRPS(theirCh), ToRPS(yourCh))
 		},
 	)
+	if err != nil {
+		return aoc.Answer{}, err
+	}
 
-	d.log.Info("score calculated", zap.Int("score", totalScore))
+	d.log.Debug("score calculated", zap.Int("score", totalScore))
 
-	return nil
+	return aoc.IntAnswer(totalScore), nil
 }
 
 // PartTwo updates the definition to say that actually, XYZ refer to whether you
@@ -204,9 +223,15 @@ func (d Day02) PartOne(_ context.Context, input string) error {
 //	Z : You need to win
 //
 // Calculate the score from the given input using the above rules.
-func (d Day02) PartTwo(_ context.Context, input string) error {
-	totalScore := RunGame(
-		input,
+func (d Day02) PartTwo(ctx context.Context, input string) (aoc.Answer, error) {
+	return d.PartTwoReader(ctx, strings.NewReader(input))
+}
+
+// PartTwoReader is PartTwo, reading the input as it goes.
+func (d Day02) PartTwoReader(ctx context.Context, r io.Reader) (aoc.Answer, error) {
+	totalScore, err := RunGame(
+		ctx,
+		r,
 		func(theirCh, yourCh uint8) (roundScore int) {
 			var (
 				theirs      = ToRPS(theirCh)
@@ -228,8 +253,11 @@ func (d Day02) PartTwo(_ context.Context, input string) error {
 			return val
 		},
 	)
+	if err != nil {
+		return aoc.Answer{}, err
+	}
 
-	d.log.Info("optimum score calculated", zap.Int("score", totalScore))
+	d.log.Debug("optimum score calculated", zap.Int("score", totalScore))
 
-	return nil
+	return aoc.IntAnswer(totalScore), nil
 }
@@ -1,9 +1,15 @@
 package day02
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/nightmarlin/aoc2022/aoc"
+	"github.com/nightmarlin/aoc2022/aoc/aoctest"
 )
 
 func TestTargetOutcomes(t *testing.T) {
@@ -40,3 +46,76 @@ func TestTargetOutcomes(t *testing.T) {
 		)
 	}
 }
+
+// exampleInput is the worked example from the puzzle description.
+const exampleInput = `A Y
+B X
+C Z
+`
+
+func TestExample(t *testing.T) {
+	var (
+		ctx = context.Background()
+		d   = New(zap.NewNop())
+	)
+
+	partOne, err := d.PartOne(ctx, exampleInput)
+	require.NoError(t, err)
+	assert.Equal(t, "15", partOne.String())
+
+	partTwo, err := d.PartTwo(ctx, exampleInput)
+	require.NoError(t, err)
+	assert.Equal(t, "12", partTwo.String())
+}
+
+// streamRepeats is how many copies of the example TestStreaming reads.
+const streamRepeats = 10_000
+
+// TestStreaming runs the example many times over, which should give the
+// example's answers multiplied by the number of copies.
+func TestStreaming(t *testing.T) {
+	var (
+		ctx = context.Background()
+		d   = New(zap.NewNop())
+	)
+
+	partOne, err := d.PartOneReader(ctx, aoctest.RepeatReader(exampleInput, streamRepeats))
+	require.NoError(t, err)
+	assert.Equal(t, aoc.IntAnswer(15*streamRepeats), partOne)
+
+	partTwo, err := d.PartTwoReader(ctx, aoctest.RepeatReader(exampleInput, streamRepeats))
+	require.NoError(t, err)
+	assert.Equal(t, aoc.IntAnswer(12*streamRepeats), partTwo)
+}
+
+func BenchmarkPartOne(b *testing.B) {
+	var (
+		ctx   = context.Background()
+		input = aoctest.Input(b, aoc.Puzzle{Year: 2022, Day: 2})
+		d     = New(zap.NewNop())
+	)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := d.PartOne(ctx, input); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPartTwo(b *testing.B) {
+	var (
+		ctx   = context.Background()
+		input = aoctest.Input(b, aoc.Puzzle{Year: 2022, Day: 2})
+		d     = New(zap.NewNop())
+	)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := d.PartTwo(ctx, input); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -2,17 +2,32 @@ package day03
 
 import (
 	"context"
+	"io"
 	"strings"
 
 	"go.uber.org/zap"
 
+	"github.com/nightmarlin/aoc2022/aoc"
 	"github.com/nightmarlin/aoc2022/lib"
+	"github.com/nightmarlin/aoc2022/registry"
 )
 
 type Day03 struct {
 	log *zap.Logger
 }
 
+var _ registry.StreamingSolution = Day03{}
+
+func init() {
+	registry.Register(registry.Entry{
+		Puzzle:     aoc.Puzzle{Year: 2022, Day: 3},
+		Title:      "Rucksack Reorganization",
+		Tags:       []string{registry.TagParsing, registry.TagSets},
+		Complexity: "O(n)",
+		New:        func(log *zap.Logger) registry.Solution { return New(log) },
+	})
+}
+
 func New(log *zap.Logger) Day03 {
 	return Day03{log: log.Named("day-03")}
 }
@@ -96,31 +111,33 @@ func Priority(c uint8) int {
 //
 // This solution models each compartment as a set and attempts to find the
 // intersection
-func (d Day03) PartOne(_ context.Context, input string) error {
-	prioritySum := lib.Reduce(
-		lib.Map(
-			strings.Split(input, "\n"),
-			func(line string) uint8 {
-				if len(line) == 0 {
-					return 0
-				}
-				compartment1, compartment2 := LineToCompartments(line)      // Convert each line to the two compartments
-				intersect := compartment1.Intersect(compartment2).Members() // Find the intersection
-				if len(intersect) == 0 {
-					return 0
-				}
-				return intersect[0]
-			},
-		),
-		func(prev int, next uint8) int { return prev + Priority(next) }, // Calculate and sum the priority for each element
-		0,
-	)
+func (d Day03) PartOne(ctx context.Context, input string) (aoc.Answer, error) {
+	return d.PartOneReader(ctx, strings.NewReader(input))
+}
+
+// PartOneReader is PartOne, reading the input a bag at a time.
+func (d Day03) PartOneReader(ctx context.Context, r io.Reader) (aoc.Answer, error) {
+	prioritySum := 0
+	err := lib.ScanLines(ctx, r, func(line string) error {
+		if len(line) == 0 {
+			return nil
+		}
+		compartment1, compartment2 := LineToCompartments(line)      // Convert each line to the two compartments
+		intersect := compartment1.Intersect(compartment2).Members() // Find the intersection
+		if len(intersect) != 0 {
+			prioritySum += Priority(intersect[0]) // Calculate and sum the priority for each element
+		}
+		return nil
+	})
+	if err != nil {
+		return aoc.Answer{}, err
+	}
 
-	d.log.Info(
+	d.log.Debug(
 		"found the sum of the priorities for items in both compartments of each bag",
 		zap.Int("sum", prioritySum),
 	)
-	return nil
+	return aoc.IntAnswer(prioritySum), nil
 }
 
 // PartTwo builds upon PartOne by looking across multiple bags at once. For each
@@ -128,37 +145,48 @@ func (d Day03) PartOne(_ context.Context, input string) error {
 // all three bags. We are now asked to identify this item, assign it a Priority
 // as in PartOne and return the sum of the priorities across every three-bag
 // group.
-func (d Day03) PartTwo(_ context.Context, input string) error {
+func (d Day03) PartTwo(ctx context.Context, input string) (aoc.Answer, error) {
+	return d.PartTwoReader(ctx, strings.NewReader(input))
+}
+
+// PartTwoReader is PartTwo, reading the input a bag at a time. Only the items
+// common to the bags seen so far in the current group are kept.
+func (d Day03) PartTwoReader(ctx context.Context, r io.Reader) (aoc.Answer, error) {
 	var (
 		total        int
+		bag          int
 		intersectSet Set[uint8]
 	)
-	for i, line := range strings.Split(input, "\n") {
-		// On the first iteration, and every three iterations after...
-		if i%3 == 0 {
-			// If the set exists
-			if intersectSet != nil {
-				// Fetch the set of items that are in all three bags and add the
-				// priority of the first to the running total. There should only be one
-				// item.
-				members := intersectSet.Members()
-				if len(members) != 0 {
-					total += Priority(members[0])
-				}
-			}
+	err := lib.ScanLines(ctx, r, func(line string) error {
+		if len(line) == 0 {
+			return nil
+		}
 
+		// The first bag of each group starts the set, and every bag after it
+		// narrows the set down to the items they have in common.
+		if bag%3 == 0 {
 			intersectSet = ConstructSet(line)
 		} else {
-
-			// On any other iteration, convert each bag to a set and find the
-			// intersection
 			intersectSet = intersectSet.Intersect(ConstructSet(line))
 		}
+
+		// Once the group's third bag has been seen, there should only be one item
+		// left in the set. Add its priority to the running total.
+		if bag%3 == 2 {
+			if members := intersectSet.Members(); len(members) != 0 {
+				total += Priority(members[0])
+			}
+		}
+		bag++
+		return nil
+	})
+	if err != nil {
+		return aoc.Answer{}, err
 	}
 
-	d.log.Info(
+	d.log.Debug(
 		"found the sum of the priorities for items in the bags of every elf in each group",
 		zap.Int("sum", total),
 	)
-	return nil
+	return aoc.IntAnswer(total), nil
 }
@@ -3,18 +3,33 @@ package day04
 import (
 	"context"
 	"fmt"
+	"io"
 	"strconv"
 	"strings"
 
 	"go.uber.org/zap"
 
+	"github.com/nightmarlin/aoc2022/aoc"
 	"github.com/nightmarlin/aoc2022/lib"
+	"github.com/nightmarlin/aoc2022/registry"
 )
 
 type Day04 struct {
 	log *zap.Logger
 }
 
+var _ registry.StreamingSolution = Day04{}
+
+func init() {
+	registry.Register(registry.Entry{
+		Puzzle:     aoc.Puzzle{Year: 2022, Day: 4},
+		Title:      "Camp Cleanup",
+		Tags:       []string{registry.TagParsing, registry.TagRanges},
+		Complexity: "O(n)",
+		New:        func(log *zap.Logger) registry.Solution { return New(log) },
+	})
+}
+
 func New(log *zap.Logger) Day04 {
 	return Day04{log: log.Named("day-04")}
 }
@@ -86,60 +101,65 @@ func GetRow(line string) (Row, error) {
 	return res, nil
 }
 
-func (d Day04) PartOne(_ context.Context, input string) error {
-	containCount := lib.Reduce(
-		lib.Map(
-			lib.Filter(strings.Split(input, "\n"), func(s string) bool { return len(s) > 0 }),
-			func(line string) bool {
-				r, err := GetRow(line)
-				if err != nil {
-					d.log.Warn("failed to parse row", zap.Error(err))
-					return false
-				}
-				ec := r.EitherContains()
-
-				d.log.Debug("row parsed", zap.Any("row", r), zap.Bool("either_contains", ec))
-				return ec
-			},
-		),
-		func(sum int, b bool) int {
-			if !b {
-				return sum
-			}
-			return sum + 1
-		},
-		0,
-	)
+// CountRows reads the input a line at a time, parsing each into a Row with
+// GetRow and counting those that satisfy pred. Rows that can't be parsed are
+// logged and skipped.
+func (d Day04) CountRows(ctx context.Context, r io.Reader, pred func(Row) bool) (int, error) {
+	count := 0
+	err := lib.ScanLines(ctx, r, func(line string) error {
+		if len(line) == 0 {
+			return nil
+		}
+
+		row, err := GetRow(line)
+		if err != nil {
+			d.log.Warn("failed to parse row", zap.Error(err))
+			return nil
+		}
+		if pred(row) {
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
 
-	d.log.Info("found number of pairs where one fully contains the other", zap.Int("count", containCount))
-	return nil
+func (d Day04) PartOne(ctx context.Context, input string) (aoc.Answer, error) {
+	return d.PartOneReader(ctx, strings.NewReader(input))
 }
 
-func (d Day04) PartTwo(_ context.Context, input string) error {
-	intersectCount := lib.Reduce(
-		lib.Map(
-			lib.Filter(strings.Split(input, "\n"), func(s string) bool { return len(s) > 0 }),
-			func(line string) bool {
-				r, err := GetRow(line)
-				if err != nil {
-					d.log.Warn("failed to parse row", zap.Error(err))
-					return false
-				}
-				ec := r.Intersect()
-
-				d.log.Debug("row parsed", zap.Any("row", r), zap.Bool("intersects", ec))
-				return ec
-			},
-		),
-		func(sum int, b bool) int {
-			if !b {
-				return sum
-			}
-			return sum + 1
-		},
-		0,
-	)
+// PartOneReader is PartOne, reading the input as it goes.
+func (d Day04) PartOneReader(ctx context.Context, r io.Reader) (aoc.Answer, error) {
+	containCount, err := d.CountRows(ctx, r, func(row Row) bool {
+		ec := row.EitherContains()
+
+		d.log.Debug("row parsed", zap.Any("row", row), zap.Bool("either_contains", ec))
+		return ec
+	})
+	if err != nil {
+		return aoc.Answer{}, err
+	}
+
+	d.log.Debug("found number of pairs where one fully contains the other", zap.Int("count", containCount))
+	return aoc.IntAnswer(containCount), nil
+}
+
+func (d Day04) PartTwo(ctx context.Context, input string) (aoc.Answer, error) {
+	return d.PartTwoReader(ctx, strings.NewReader(input))
+}
+
+// PartTwoReader is PartTwo, reading the input as it goes.
+func (d Day04) PartTwoReader(ctx context.Context, r io.Reader) (aoc.Answer, error) {
+	intersectCount, err := d.CountRows(ctx, r, func(row Row) bool {
+		ec := row.Intersect()
+
+		d.log.Debug("row parsed", zap.Any("row", row), zap.Bool("intersects", ec))
+		return ec
+	})
+	if err != nil {
+		return aoc.Answer{}, err
+	}
 
-	d.log.Info("found number of pairs where one intersects with the other", zap.Int("count", intersectCount))
-	return nil
+	d.log.Debug("found number of pairs where one intersects with the other", zap.Int("count", intersectCount))
+	return aoc.IntAnswer(intersectCount), nil
 }
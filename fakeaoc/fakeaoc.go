@@ -0,0 +1,433 @@
+// Package fakeaoc serves a stand-in for Advent of Code from a directory of
+// fixtures, so that the aoc package can be developed, tested and demonstrated
+// without a network. It answers the endpoints the aoc package uses, checking
+// session cookies, holding puzzles back until they unlock and rate limiting
+// requests much as AoC does.
+//
+// The fixtures directory is laid out as:
+//
+//	<year>/<day>/input                the puzzle input, served to every user
+//	<year>/<day>/part1.html           part one's <article class="day-desc">
+//	<year>/<day>/part2.html           part two's, shown once part one is solved
+//	<year>/<day>/answers              the answer to each part, one per line
+//	<year>/leaderboards/<id>.json     a private leaderboard
+//
+// Days are not zero padded, matching AoC's URLs.
+//
+// It deliberately doesn't import the aoc package, so that it stays a faithful
+// imitation of the real site rather than of the client's idea of it.
+package fakeaoc
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Config configures a Server.
+type Config struct {
+	// Fixtures is the directory the puzzles are served from.
+	Fixtures string
+
+	// Sessions maps each session cookie the Server accepts to the name of the
+	// user it belongs to.
+	Sessions map[string]string
+
+	// Now returns the current time, which decides which puzzles have unlocked.
+	// It defaults to time.Now.
+	Now func() time.Time
+
+	// MinInterval is the least time allowed between two requests with the same
+	// session. Requests sooner than that are answered with 429 Too Many
+	// Requests and a Retry-After header. Zero disables rate limiting.
+	MinInterval time.Duration
+
+	// AnswerCooldown is how long a user has to wait after a wrong answer before
+	// submitting another for the same puzzle.
+	AnswerCooldown time.Duration
+}
+
+// A Server is a fake AoC. It keeps track of which parts each user has solved
+// in memory, so every Server starts with nothing solved.
+type Server struct {
+	cfg Config
+
+	mu    sync.Mutex
+	users map[string]*user // users are keyed by session cookie.
+}
+
+type puzzle struct {
+	Year, Day int
+}
+
+type user struct {
+	name string
+
+	lastRequest time.Time
+	solved      map[puzzle]int       // solved is how many parts of each puzzle are solved.
+	cooldowns   map[puzzle]time.Time // cooldowns are when answers may next be given.
+}
+
+// unlockZone is the time zone puzzles unlock at midnight in.
+var unlockZone = time.FixedZone("EST", -5*60*60)
+
+// New creates a Server serving the fixtures in cfg.
+func New(cfg Config) *Server {
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+
+	users := make(map[string]*user, len(cfg.Sessions))
+	for cookie, name := range cfg.Sessions {
+		users[cookie] = &user{
+			name:      name,
+			solved:    make(map[puzzle]int),
+			cooldowns: make(map[puzzle]time.Time),
+		}
+	}
+	return &Server{cfg: cfg, users: users}
+}
+
+var (
+	calendarPath    = regexp.MustCompile(`^/(\d+)/?$`)
+	puzzlePath      = regexp.MustCompile(`^/(\d+)/day/(\d+)$`)
+	inputPath       = regexp.MustCompile(`^/(\d+)/day/(\d+)/input$`)
+	answerPath      = regexp.MustCompile(`^/(\d+)/day/(\d+)/answer$`)
+	leaderboardPath = regexp.MustCompile(`^/(\d+)/leaderboard/private/view/(\d+)\.json$`)
+	privatePath     = regexp.MustCompile(`^/(\d+)/leaderboard/private$`)
+)
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u := s.user(r)
+	if u != nil && s.cfg.MinInterval > 0 {
+		now := s.cfg.Now()
+		if wait := u.lastRequest.Add(s.cfg.MinInterval).Sub(now); wait > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			http.Error(w, "429 Too Many Requests", http.StatusTooManyRequests)
+			return
+		}
+		u.lastRequest = now
+	}
+
+	path := r.URL.Path
+	switch {
+	case r.Method == http.MethodPost && answerPath.MatchString(path):
+		s.serveAnswer(w, r, u, parsePuzzle(answerPath, path))
+	case r.Method != http.MethodGet:
+		http.Error(w, "405 Method Not Allowed", http.StatusMethodNotAllowed)
+	case calendarPath.MatchString(path):
+		s.serveCalendar(w, u, parseYear(calendarPath, path))
+	case puzzlePath.MatchString(path):
+		s.servePuzzle(w, u, parsePuzzle(puzzlePath, path))
+	case inputPath.MatchString(path):
+		s.serveInput(w, u, parsePuzzle(inputPath, path))
+	case leaderboardPath.MatchString(path):
+		s.serveLeaderboard(w, r, u, path)
+	case privatePath.MatchString(path):
+		s.writePage(w, u, "Private Leaderboard", "<article><p>You don't have access to that private leaderboard.</p></article>")
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// user returns the user whose session cookie the request carries, or nil if it
+// has none the Server accepts.
+func (s *Server) user(r *http.Request) *user {
+	c, err := r.Cookie("session")
+	if err != nil {
+		return nil
+	}
+	return s.users[c.Value]
+}
+
+func parseYear(pattern *regexp.Regexp, path string) int {
+	year, _ := strconv.Atoi(pattern.FindStringSubmatch(path)[1])
+	return year
+}
+
+func parsePuzzle(pattern *regexp.Regexp, path string) puzzle {
+	m := pattern.FindStringSubmatch(path)
+	year, _ := strconv.Atoi(m[1])
+	day, _ := strconv.Atoi(m[2])
+	return puzzle{Year: year, Day: day}
+}
+
+// unlocked reports whether the puzzle has been released, writing AoC's response
+// to requests that come too early if it hasn't.
+func (s *Server) unlocked(w http.ResponseWriter, p puzzle) bool {
+	unlock := time.Date(p.Year, time.December, p.Day, 0, 0, 0, 0, unlockZone)
+	if s.cfg.Now().Before(unlock) {
+		http.Error(
+			w,
+			"Please don't repeatedly request this endpoint before it unlocks! The calendar countdown is synchronized with the server time; the link will be enabled on the calendar the instant this puzzle becomes available.",
+			http.StatusNotFound,
+		)
+		return false
+	}
+	return true
+}
+
+// region endpoints
+
+func (s *Server) serveCalendar(w http.ResponseWriter, u *user, year int) {
+	s.writePage(w, u, fmt.Sprintf("Advent of Code %d", year), "<p>The calendar is yet to be drawn.</p>")
+}
+
+func (s *Server) servePuzzle(w http.ResponseWriter, u *user, p puzzle) {
+	if !s.unlocked(w, p) {
+		return
+	}
+
+	partOne, err := s.readFixture(p, "part1.html")
+	if err != nil {
+		s.fixtureError(w, err)
+		return
+	}
+
+	var (
+		main    strings.Builder
+		answers = s.answers(p)
+		solved  = 0
+	)
+	if u != nil {
+		solved = u.solved[p]
+	}
+
+	main.WriteString(partOne)
+	if solved >= 1 {
+		fmt.Fprintf(&main, "\n<p>Your puzzle answer was <code>%s</code>.</p>", html.EscapeString(answers[0]))
+
+		partTwo, err := s.readFixture(p, "part2.html")
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			s.fixtureError(w, err)
+			return
+		}
+		main.WriteString(partTwo)
+	}
+
+	switch {
+	case solved >= 2:
+		fmt.Fprintf(&main, "\n<p>Your puzzle answer was <code>%s</code>.</p>", html.EscapeString(answers[1]))
+		main.WriteString(`<p class="day-success">Both parts of this puzzle are complete! They provide two gold stars: **</p>`)
+	case u != nil:
+		fmt.Fprintf(
+			&main,
+			"\n"+`<form method="post" action="%d/answer"><input type="hidden" name="level" value="%d"/><p>Answer: <input type="text" name="answer" autocomplete="off"/> <input type="submit" value="[Submit]"/></p></form>`,
+			p.Day, solved+1,
+		)
+	default:
+		main.WriteString("\n<p>To play, please identify yourself via one of these services.</p>")
+	}
+
+	s.writePage(w, u, fmt.Sprintf("Day %d - Advent of Code %d", p.Day, p.Year), main.String())
+}
+
+func (s *Server) serveInput(w http.ResponseWriter, u *user, p puzzle) {
+	if !s.unlocked(w, p) {
+		return
+	}
+	if u == nil {
+		http.Error(w, "Puzzle inputs differ by user.  Please log in to get your puzzle input.", http.StatusBadRequest)
+		return
+	}
+
+	input, err := s.readFixture(p, "input")
+	if err != nil {
+		s.fixtureError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain")
+	_, _ = w.Write([]byte(input))
+}
+
+func (s *Server) serveAnswer(w http.ResponseWriter, r *http.Request, u *user, p puzzle) {
+	if !s.unlocked(w, p) {
+		return
+	}
+	if u == nil {
+		http.Error(w, "Please log in to submit answers.", http.StatusBadRequest)
+		return
+	}
+
+	level, err := strconv.Atoi(r.PostFormValue("level"))
+	if err != nil {
+		http.Error(w, "400 Bad Request", http.StatusBadRequest)
+		return
+	}
+	answer := strings.TrimSpace(r.PostFormValue("answer"))
+
+	answers := s.answers(p)
+	now := s.cfg.Now()
+
+	var msg string
+	switch {
+	case level != u.solved[p]+1 || level > len(answers):
+		msg = fmt.Sprintf(`You don't seem to be solving the right level.  Did you already complete it? <a href="/%d/day/%d">[Return to Day %d]</a>`, p.Year, p.Day, p.Day)
+
+	case now.Before(u.cooldowns[p]):
+		msg = fmt.Sprintf(
+			"You gave an answer too recently; you have to wait after submitting an answer before trying again.  You have %s left to wait.",
+			formatWait(u.cooldowns[p].Sub(now)),
+		)
+
+	case answer == answers[level-1]:
+		u.solved[p] = level
+		msg = "That's the right answer!  You are one gold star closer to saving your vacation."
+
+	default:
+		msg = "That's not the right answer"
+		if direction := compareAnswers(answer, answers[level-1]); direction != "" {
+			msg += "; your answer is " + direction
+		}
+		msg += ".  If you're stuck, make sure you're using the full input data."
+
+		if s.cfg.AnswerCooldown > 0 {
+			u.cooldowns[p] = now.Add(s.cfg.AnswerCooldown)
+
+			minutes := int(math.Ceil(s.cfg.AnswerCooldown.Minutes()))
+			if minutes == 1 {
+				msg += "  Please wait one minute before trying again."
+			} else {
+				msg += fmt.Sprintf("  Please wait %d minutes before trying again.", minutes)
+			}
+		}
+	}
+
+	s.writePage(w, u, fmt.Sprintf("Day %d - Advent of Code %d", p.Day, p.Year), "<article><p>"+msg+"</p></article>")
+}
+
+func (s *Server) serveLeaderboard(w http.ResponseWriter, r *http.Request, u *user, path string) {
+	m := leaderboardPath.FindStringSubmatch(path)
+	year, id := m[1], m[2]
+
+	// AoC sends anyone who can't see the leaderboard to a page explaining so.
+	redirect := func() {
+		http.Redirect(w, r, "/"+year+"/leaderboard/private", http.StatusFound)
+	}
+	if u == nil {
+		redirect()
+		return
+	}
+
+	data, err := os.ReadFile(filepath.Join(s.cfg.Fixtures, year, "leaderboards", id+".json"))
+	if err != nil {
+		redirect()
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(data)
+}
+
+// endregion
+
+// region fixtures
+
+func (s *Server) readFixture(p puzzle, name string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(s.cfg.Fixtures, strconv.Itoa(p.Year), strconv.Itoa(p.Day), name))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// answers returns the answer to each part of the puzzle that has one.
+func (s *Server) answers(p puzzle) []string {
+	data, err := s.readFixture(p, "answers")
+	if err != nil {
+		return nil
+	}
+
+	var res []string
+	for _, line := range strings.Split(data, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			res = append(res, line)
+		}
+	}
+	return res
+}
+
+func (s *Server) fixtureError(w http.ResponseWriter, err error) {
+	if errors.Is(err, os.ErrNotExist) {
+		http.Error(w, "404 Not Found", http.StatusNotFound)
+		return
+	}
+	http.Error(w, "500 Internal Server Error: "+err.Error(), http.StatusInternalServerError)
+}
+
+// endregion
+
+// compareAnswers says whether a wrong answer is "too high" or "too low", when
+// both it and the right answer are numbers.
+func compareAnswers(answer, want string) string {
+	a, err := strconv.ParseInt(answer, 10, 64)
+	if err != nil {
+		return ""
+	}
+	b, err := strconv.ParseInt(want, 10, 64)
+	if err != nil {
+		return ""
+	}
+
+	if a > b {
+		return "too high"
+	}
+	return "too low"
+}
+
+// formatWait formats a wait in the way AoC does, such as "1m 5s".
+func formatWait(d time.Duration) string {
+	secs := int(math.Ceil(d.Seconds()))
+	if secs >= 60 {
+		return fmt.Sprintf("%dm %ds", secs/60, secs%60)
+	}
+	return fmt.Sprintf("%ds", secs)
+}
+
+// writePage writes main inside the markup AoC wraps every page in. The header
+// shows who is logged in, and how many stars they have, as AoC's does.
+func (s *Server) writePage(w http.ResponseWriter, u *user, title, main string) {
+	userDiv := ""
+	if u != nil {
+		stars := 0
+		for _, n := range u.solved {
+			stars += n
+		}
+		userDiv = fmt.Sprintf(`<div class="user">%s <span class="star-count">%d*</span></div>`, html.EscapeString(u.name), stars)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = fmt.Fprintf(
+		w,
+		`<!DOCTYPE html>
+<html lang="en-us">
+<head>
+<meta charset="utf-8"/>
+<title>%s</title>
+</head>
+<body>
+<header><div><h1 class="title-global"><a href="/">Advent of Code</a></h1>%s</div></header>
+
+<main>
+%s
+</main>
+
+</body>
+</html>
+`,
+		html.EscapeString(title), userDiv, main,
+	)
+}
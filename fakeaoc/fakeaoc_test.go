@@ -0,0 +1,178 @@
+package fakeaoc
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSession = "test-session"
+
+// testClock is a settable clock for Config.Now.
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time { return c.now }
+
+// newTestServer serves the testdata fixtures, with the clock set to when day 1
+// of 2022 unlocked.
+func newTestServer(t *testing.T, cfg Config) (*httptest.Server, *testClock) {
+	t.Helper()
+
+	clock := &testClock{now: time.Date(2022, time.December, 1, 5, 0, 0, 0, time.UTC)}
+	cfg.Fixtures = "testdata"
+	cfg.Sessions = map[string]string{testSession: "Example User"}
+	cfg.Now = clock.Now
+
+	srv := httptest.NewServer(New(cfg))
+	t.Cleanup(srv.Close)
+	return srv, clock
+}
+
+// get requests the path, with the session cookie if session is set, returning
+// the response's status and body.
+func get(t *testing.T, srv *httptest.Server, path string, session bool) (int, string) {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+path, nil)
+	require.NoError(t, err)
+	return do(t, req, session)
+}
+
+// post submits an answer for day 1 of 2022.
+func post(t *testing.T, srv *httptest.Server, level, answer string) string {
+	t.Helper()
+
+	form := url.Values{"level": {level}, "answer": {answer}}
+	req, err := http.NewRequest(http.MethodPost, srv.URL+"/2022/day/1/answer", strings.NewReader(form.Encode()))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	status, body := do(t, req, true)
+	require.Equal(t, http.StatusOK, status, body)
+	return body
+}
+
+func do(t *testing.T, req *http.Request, session bool) (int, string) {
+	t.Helper()
+
+	if session {
+		req.AddCookie(&http.Cookie{Name: "session", Value: testSession})
+	}
+
+	client := &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(body)
+}
+
+func TestUnlock(t *testing.T) {
+	srv, clock := newTestServer(t, Config{})
+
+	clock.now = time.Date(2022, time.December, 1, 4, 59, 59, 0, time.UTC)
+	status, body := get(t, srv, "/2022/day/1/input", true)
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Contains(t, body, "before it unlocks")
+
+	clock.now = clock.now.Add(time.Second)
+	status, body = get(t, srv, "/2022/day/1/input", true)
+	assert.Equal(t, http.StatusOK, status)
+	assert.True(t, strings.HasPrefix(body, "1000\n2000\n"), body)
+
+	status, _ = get(t, srv, "/2022/day/2/input", true)
+	assert.Equal(t, http.StatusNotFound, status, "days without fixtures should not be found")
+}
+
+func TestSession(t *testing.T) {
+	srv, _ := newTestServer(t, Config{})
+
+	status, body := get(t, srv, "/2022/day/1/input", false)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Contains(t, body, "Please log in")
+
+	_, body = get(t, srv, "/2022", false)
+	assert.NotContains(t, body, `<div class="user">`)
+
+	_, body = get(t, srv, "/2022", true)
+	assert.Contains(t, body, `<div class="user">Example User <span class="star-count">0*</span></div>`)
+}
+
+func TestAnswers(t *testing.T) {
+	srv, clock := newTestServer(t, Config{AnswerCooldown: time.Minute})
+
+	_, page := get(t, srv, "/2022/day/1", true)
+	assert.NotContains(t, page, "Part Two")
+	assert.Contains(t, page, `name="level" value="1"`)
+
+	assert.Contains(t, post(t, srv, "2", "45000"), "You don't seem to be solving the right level")
+	assert.Contains(t, post(t, srv, "1", "30000"), "your answer is too high.  If you're stuck, make sure you're using the full input data.  Please wait one minute before trying again.")
+
+	clock.now = clock.now.Add(55 * time.Second)
+	assert.Contains(t, post(t, srv, "1", "24000"), "You have 5s left to wait.")
+
+	clock.now = clock.now.Add(5 * time.Second)
+	assert.Contains(t, post(t, srv, "1", "24000"), "That's the right answer!")
+
+	_, page = get(t, srv, "/2022/day/1", true)
+	assert.Contains(t, page, "Your puzzle answer was <code>24000</code>.")
+	assert.Contains(t, page, "--- Part Two ---")
+	assert.Contains(t, page, `name="level" value="2"`)
+
+	assert.Contains(t, post(t, srv, "2", "oops"), "That's not the right answer.  If you're stuck")
+	clock.now = clock.now.Add(time.Minute)
+	assert.Contains(t, post(t, srv, "2", "45000"), "That's the right answer!")
+	assert.Contains(t, post(t, srv, "2", "45000"), "You don't seem to be solving the right level")
+
+	_, page = get(t, srv, "/2022/day/1", true)
+	assert.Contains(t, page, "Both parts of this puzzle are complete!")
+	assert.Contains(t, page, `<span class="star-count">2*</span>`)
+}
+
+func TestRateLimit(t *testing.T) {
+	srv, clock := newTestServer(t, Config{MinInterval: 3 * time.Second})
+
+	status, _ := get(t, srv, "/2022/day/1/input", true)
+	assert.Equal(t, http.StatusOK, status)
+
+	clock.now = clock.now.Add(time.Second)
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/2022/day/1/input", nil)
+	require.NoError(t, err)
+	req.AddCookie(&http.Cookie{Name: "session", Value: testSession})
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "2", resp.Header.Get("Retry-After"))
+
+	clock.now = clock.now.Add(2 * time.Second)
+	status, _ = get(t, srv, "/2022/day/1/input", true)
+	assert.Equal(t, http.StatusOK, status)
+}
+
+func TestLeaderboard(t *testing.T) {
+	srv, _ := newTestServer(t, Config{})
+
+	status, _ := get(t, srv, "/2022/leaderboard/private/view/101.json", false)
+	assert.Equal(t, http.StatusFound, status, "should redirect without a session")
+
+	status, _ = get(t, srv, "/2022/leaderboard/private/view/999.json", true)
+	assert.Equal(t, http.StatusFound, status, "should redirect for unknown leaderboards")
+
+	status, body := get(t, srv, "/2022/leaderboard/private/view/101.json", true)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Contains(t, body, `"owner_id": 101`)
+}
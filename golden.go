@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/nightmarlin/aoc2022/aoc"
+)
+
+const defaultGoldenFile = "answers.json"
+
+// goldenAnswers are the confirmed answers for each day's input, keyed by year,
+// day and then part. They are checked in so that refactors can be verified
+// against them.
+type goldenAnswers map[string]map[string]map[string]aoc.Answer
+
+func loadGoldenAnswers(path string) (goldenAnswers, error) {
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return goldenAnswers{}, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to read golden answers: %w", err)
+	}
+
+	var g goldenAnswers
+	if err := json.Unmarshal(data, &g); err != nil {
+		return nil, fmt.Errorf("failed to parse golden answers %q: %w", path, err)
+	}
+	if g == nil {
+		g = goldenAnswers{}
+	}
+	return g, nil
+}
+
+func (g goldenAnswers) Get(p aoc.Puzzle, part int) (aoc.Answer, bool) {
+	a, ok := g[strconv.Itoa(p.Year)][strconv.Itoa(p.Day)][strconv.Itoa(part)]
+	return a, ok
+}
+
+func (g goldenAnswers) Set(p aoc.Puzzle, part int, answer aoc.Answer) {
+	y, d := strconv.Itoa(p.Year), strconv.Itoa(p.Day)
+	if g[y] == nil {
+		g[y] = make(map[string]map[string]aoc.Answer)
+	}
+	if g[y][d] == nil {
+		g[y][d] = make(map[string]aoc.Answer)
+	}
+	g[y][d][strconv.Itoa(part)] = answer
+}
+
+func (g goldenAnswers) Save(path string) error {
+	data, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode golden answers: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write golden answers: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/nightmarlin/aoc2022/aoc/aoctest"
+	"github.com/nightmarlin/aoc2022/registry"
+)
+
+// TestGoldenAnswers re-runs every solution that has a golden answer against its
+// cached input. Days whose input hasn't been fetched are skipped, but a day
+// whose input has been fetched without a golden answer being recorded fails,
+// as otherwise refactors of it would go unchecked.
+func TestGoldenAnswers(t *testing.T) {
+	golden, err := loadGoldenAnswers(defaultGoldenFile)
+	require.NoError(t, err)
+
+	if len(golden) == 0 {
+		t.Logf(
+			"WARNING: %s has no golden answers, so no solution is checked - run `go run . verify -update` once your answers are confirmed",
+			defaultGoldenFile,
+		)
+	}
+
+	for _, entry := range registry.All() {
+		entry, puzzle := entry, entry.Puzzle
+		t.Run(
+			puzzle.String(),
+			func(t *testing.T) {
+				var (
+					input     = aoctest.Input(t, puzzle)
+					_, hasOne = golden.Get(puzzle, 1)
+					_, hasTwo = golden.Get(puzzle, 2)
+					solution  = entry.New(zap.NewNop())
+				)
+				if !hasOne && !hasTwo {
+					t.Fatalf(
+						"the input for %s is cached but %s has no golden answers for it - run `go run . verify -update %s` once they're confirmed",
+						puzzle, defaultGoldenFile, puzzle,
+					)
+				}
+
+				for part := 1; part <= 2; part++ {
+					want, ok := golden.Get(puzzle, part)
+					if !ok {
+						continue
+					}
+
+					got, err := runPart(context.Background(), solution, part, input)
+					require.NoError(t, err)
+					assert.Equal(t, want.String(), got.String(), "part %d", part)
+				}
+			},
+		)
+	}
+}
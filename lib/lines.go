@@ -0,0 +1,37 @@
+package lib
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"math"
+)
+
+// ctxCheckInterval is how many lines ScanLines reads between checks of the
+// context.
+const ctxCheckInterval = 1024
+
+// scanBufferSize is the size of the buffer ScanLines starts with, which grows
+// to fit longer lines.
+const scanBufferSize = 64 * 1024
+
+// ScanLines calls fn with each line read from r, without its line ending. Only
+// one line is held at a time, so memory use doesn't grow with the size of the
+// input - though it does with the length of the longest line, as lines have no
+// length limit. It stops at the first error returned by fn, or once ctx is
+// done.
+func ScanLines(ctx context.Context, r io.Reader, fn func(line string) error) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, scanBufferSize), math.MaxInt)
+	for n := 0; sc.Scan(); n++ {
+		if n%ctxCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+		if err := fn(sc.Text()); err != nil {
+			return err
+		}
+	}
+	return sc.Err()
+}
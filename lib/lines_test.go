@@ -0,0 +1,34 @@
+package lib
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanLines(t *testing.T) {
+	long := strings.Repeat("1", 4*scanBufferSize)
+
+	var lines []string
+	err := ScanLines(
+		context.Background(),
+		strings.NewReader("a\n"+long+"\n\nb"),
+		func(line string) error {
+			lines = append(lines, line)
+			return nil
+		},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", long, "", "b"}, lines)
+}
+
+func TestScanLinesCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := ScanLines(ctx, strings.NewReader("a\nb\n"), func(string) error { return nil })
+	assert.ErrorIs(t, err, context.Canceled)
+}
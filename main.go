@@ -1,140 +1,77 @@
 package main
 
 import (
-	"bufio"
 	"context"
 	"fmt"
+	"io"
 	"os"
-	"sort"
-	"strconv"
-	"strings"
+	"os/signal"
 
 	"go.uber.org/zap"
 
 	"github.com/nightmarlin/aoc2022/aoc"
-	"github.com/nightmarlin/aoc2022/day01"
-	"github.com/nightmarlin/aoc2022/day02"
-	"github.com/nightmarlin/aoc2022/day03"
-	"github.com/nightmarlin/aoc2022/day04"
 	"github.com/nightmarlin/aoc2022/lib"
-)
-
-// A Solution carries out some task on the input, as defined by the AoC
-// challenge. Solutions are expected to gracefully handle cancelled contexts if
-// there is a likelihood of them running for extended time periods.
-type Solution interface {
-	// PartOne of a Solution is generally a specific application of a problem
-	// statement.
-	PartOne(ctx context.Context, input string) error
-
-	// PartTwo of a Solution is typically a more generalised form of the problem
-	// presented in PartOne, using the same input.
-	PartTwo(ctx context.Context, input string) error
-}
+	"github.com/nightmarlin/aoc2022/registry"
 
-var solutions = map[string]func(*zap.Logger) Solution{
-	"1": func(log *zap.Logger) Solution { return day01.New(log) },
-	"2": func(log *zap.Logger) Solution { return day02.New(log) },
-	"3": func(log *zap.Logger) Solution { return day03.New(log) },
-	"4": func(log *zap.Logger) Solution { return day04.New(log) },
-}
+	// Each day registers its Solution with the registry when imported.
+	_ "github.com/nightmarlin/aoc2022/day01"
+	_ "github.com/nightmarlin/aoc2022/day02"
+	_ "github.com/nightmarlin/aoc2022/day03"
+	_ "github.com/nightmarlin/aoc2022/day04"
+)
 
-func initLogger() *zap.Logger {
+func initLogger(trace bool) (*zap.Logger, error) {
 	cfg := zap.NewDevelopmentConfig()
 	cfg.Level.SetLevel(zap.InfoLevel)
 	cfg.DisableStacktrace = true
 
-	if os.Getenv("TRACE") != "" {
+	if trace {
 		cfg.Level.SetLevel(zap.DebugLevel)
 		cfg.DisableStacktrace = false
 	}
 
-	log, err := cfg.Build()
-	if err != nil {
-		_, _ = fmt.Fprintf(os.Stderr, "failed to init logger: %s\n", err.Error())
-		os.Exit(1)
-	}
-
-	return log
+	return cfg.Build()
 }
 
 func main() {
-	ctx := context.Background()
-	log := initLogger()
-
-	sessionCookie := os.Getenv("SESSION_COOKIE")
-	if sessionCookie == "" {
-		log.Fatal("session cookie must be set to fetch aoc inputs")
-	}
-	localFolder := os.Getenv("LOCAL_FOLDER")
-	if localFolder == "" {
-		localFolder = "inputs"
-	}
-
-	day := os.Getenv("SOLUTION")
-	if day == "" {
-		log.Info(
-			"please choose a solution to run",
-			zap.Ints("available days", sortedSolutionNames()),
-		)
-
-		read, err := bufio.NewReader(os.Stdin).ReadString('\n')
-		if err != nil {
-			log.Fatal("failed to read input from stdin", zap.Error(err))
-		}
-		day = read
-	} else {
-		log.Info("solution set via environment variable", zap.String("day", day))
-	}
-	day = strings.Trim(day, "0\n")
-
-	sInit, ok := solutions[day]
-	if !ok {
-		log.Fatal("the specified solution has not been completed or does not exist", zap.String("day", day))
-	}
-
-	fetcher, err := aoc.NewFetcher(log, sessionCookie, localFolder)
-	if err != nil {
-		log.Fatal("failed to init aoc fetcher", zap.Error(err))
-	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	code := runCLI(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	stop()
+	os.Exit(code)
+}
 
-	input, err := fetcher.FetchInput(ctx, day)
-	if err != nil {
-		log.Fatal("unable to get input for chosen day", zap.Error(err))
+// entriesWithTag returns the registered entries with the tag, or all of them if
+// the tag is empty.
+func entriesWithTag(tag string) []registry.Entry {
+	if tag == "" {
+		return registry.All()
 	}
+	return lib.Filter(registry.All(), func(e registry.Entry) bool { return e.HasTag(tag) })
+}
 
-	log.Info("input fetched, initializing solution")
-	solution := sInit(log)
-
-	log.Info("solution initialized, running part one...")
-	err = solution.PartOne(ctx, input)
-	if err != nil {
-		log.Fatal("error occurred while running solution part one", zap.Error(err))
-	}
+// puzzlesOf returns the Puzzle of each entry.
+func puzzlesOf(entries []registry.Entry) []aoc.Puzzle {
+	return lib.Map(entries, func(e registry.Entry) aoc.Puzzle { return e.Puzzle })
+}
 
-	log.Info("part one complete, running part two...")
-	err = solution.PartTwo(ctx, input)
-	if err != nil {
-		log.Fatal("error occurred while running solution part two", zap.Error(err))
+// runPart runs a single part of the Solution, where part is 1 or 2.
+func runPart(ctx context.Context, s registry.Solution, part int, input string) (aoc.Answer, error) {
+	switch part {
+	case 1:
+		return s.PartOne(ctx, input)
+	case 2:
+		return s.PartTwo(ctx, input)
 	}
-
-	log.Info("complete!")
+	return aoc.Answer{}, fmt.Errorf("part must be 1 or 2, got %d", part)
 }
 
-// Map iteration is non-deterministic, so we need to manually sort the keys.
-func sortedSolutionNames() []int {
-	asNums := lib.Map(
-		lib.Keys(solutions),
-		func(k string) int {
-			i, err := strconv.ParseInt(k, 10, 32)
-			if err != nil {
-				return 0
-			}
-			return int(i)
-		},
-	)
-
-	sort.SliceStable(asNums, func(i, j int) bool { return asNums[i] < asNums[j] })
-
-	return asNums
+// runPartReader is runPart for a StreamingSolution, reading the input from r.
+func runPartReader(ctx context.Context, s registry.StreamingSolution, part int, r io.Reader) (aoc.Answer, error) {
+	switch part {
+	case 1:
+		return s.PartOneReader(ctx, r)
+	case 2:
+		return s.PartTwoReader(ctx, r)
+	}
+	return aoc.Answer{}, fmt.Errorf("part must be 1 or 2, got %d", part)
 }
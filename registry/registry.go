@@ -0,0 +1,125 @@
+// Package registry holds every Solution in the repository. Each dayNN package
+// registers itself from init, so adding a day only needs a blank import of the
+// package in main.
+package registry
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/nightmarlin/aoc2022/aoc"
+	"github.com/nightmarlin/aoc2022/lib"
+)
+
+// A Solution carries out some task on the input, as defined by the AoC
+// challenge, and hands back the Answer for each part. Solutions are expected to
+// gracefully handle cancelled contexts if there is a likelihood of them running
+// for extended time periods.
+type Solution interface {
+	// PartOne of a Solution is generally a specific application of a problem
+	// statement.
+	PartOne(ctx context.Context, input string) (aoc.Answer, error)
+
+	// PartTwo of a Solution is typically a more generalised form of the problem
+	// presented in PartOne, using the same input.
+	PartTwo(ctx context.Context, input string) (aoc.Answer, error)
+}
+
+// A StreamingSolution is a Solution that can also read its input as it goes,
+// for inputs too large to hold in memory at once. Its memory use shouldn't grow
+// with the size of the input.
+type StreamingSolution interface {
+	Solution
+
+	// PartOneReader is PartOne, reading the input from r.
+	PartOneReader(ctx context.Context, r io.Reader) (aoc.Answer, error)
+
+	// PartTwoReader is PartTwo, reading the input from r.
+	PartTwoReader(ctx context.Context, r io.Reader) (aoc.Answer, error)
+}
+
+// Tags describing the kind of problem a puzzle poses. Entries may use others,
+// but sticking to these keeps filtering useful.
+const (
+	TagParsing    = "parsing"
+	TagSorting    = "sorting"
+	TagSets       = "sets"
+	TagRanges     = "ranges"
+	TagGrid       = "grid"
+	TagGraph      = "graph"
+	TagSimulation = "simulation"
+	TagMaths      = "maths"
+)
+
+// An Entry describes a Solution to a single Puzzle.
+type Entry struct {
+	Puzzle aoc.Puzzle
+
+	// Title is the name of the puzzle, without the "Day N:" prefix.
+	Title string
+	Tags  []string
+
+	// Complexity is the expected time complexity of the Solution in terms of
+	// the input size, such as "O(n log n)".
+	Complexity string
+
+	New func(*zap.Logger) Solution
+}
+
+// HasTag reports whether the Entry is tagged with tag.
+func (e Entry) HasTag(tag string) bool {
+	return lib.Any(e.Tags, func(t string) bool { return t == tag })
+}
+
+var (
+	mu      sync.RWMutex
+	entries = make(map[aoc.Puzzle]Entry)
+)
+
+// Register adds the Entry to the registry. Like database/sql.Register, it
+// panics if the Entry is invalid or its Puzzle is already registered, as both
+// are programming errors.
+func Register(e Entry) {
+	if err := e.Puzzle.Validate(); err != nil {
+		panic(fmt.Sprintf("registry: invalid puzzle: %s", err))
+	}
+	if e.New == nil {
+		panic(fmt.Sprintf("registry: %s registered without a constructor", e.Puzzle))
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if _, ok := entries[e.Puzzle]; ok {
+		panic(fmt.Sprintf("registry: %s registered twice", e.Puzzle))
+	}
+	entries[e.Puzzle] = e
+}
+
+// Lookup finds the Entry for the Puzzle.
+func Lookup(p aoc.Puzzle) (Entry, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	e, ok := entries[p]
+	return e, ok
+}
+
+// All returns every registered Entry, in chronological order.
+func All() []Entry {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	res := make([]Entry, 0, len(entries))
+	for _, e := range entries {
+		res = append(res, e)
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Puzzle.Less(res[j].Puzzle) })
+	return res
+}
@@ -0,0 +1,53 @@
+package registry
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/nightmarlin/aoc2022/aoc"
+)
+
+type stubSolution struct{}
+
+func (stubSolution) PartOne(context.Context, string) (aoc.Answer, error) {
+	return aoc.IntAnswer(1), nil
+}
+func (stubSolution) PartTwo(context.Context, string) (aoc.Answer, error) {
+	return aoc.IntAnswer(2), nil
+}
+
+func TestRegister(t *testing.T) {
+	newStub := func(*zap.Logger) Solution { return stubSolution{} }
+
+	Register(Entry{Puzzle: aoc.Puzzle{Year: 2015, Day: 2}, Tags: []string{TagMaths}, New: newStub})
+	Register(Entry{Puzzle: aoc.Puzzle{Year: 2015, Day: 1}, Tags: []string{TagParsing}, New: newStub})
+
+	e, ok := Lookup(aoc.Puzzle{Year: 2015, Day: 2})
+	assert.True(t, ok)
+	assert.True(t, e.HasTag(TagMaths))
+	assert.False(t, e.HasTag(TagGrid))
+
+	all := All()
+	if assert.Len(t, all, 2) {
+		assert.Equal(t, aoc.Puzzle{Year: 2015, Day: 1}, all[0].Puzzle, "entries should be in order")
+	}
+
+	assert.Panics(
+		t,
+		func() { Register(Entry{Puzzle: aoc.Puzzle{Year: 2015, Day: 1}, New: newStub}) },
+		"registering a puzzle twice should panic",
+	)
+	assert.Panics(
+		t,
+		func() { Register(Entry{Puzzle: aoc.Puzzle{Year: 2015, Day: 26}, New: newStub}) },
+		"registering an invalid puzzle should panic",
+	)
+	assert.Panics(
+		t,
+		func() { Register(Entry{Puzzle: aoc.Puzzle{Year: 2015, Day: 3}}) },
+		"registering without a constructor should panic",
+	)
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
+	"github.com/nightmarlin/aoc2022/aoc"
+	"github.com/nightmarlin/aoc2022/registry"
+)
+
+// An accountResult is the outcome of running a day against one account's input.
+type accountResult struct {
+	Account  string
+	Results  []partResult
+	Statuses []string
+}
+
+// runAccounts runs the entry's solution against every account's input, and
+// prints the answers side by side. Each answer is checked against the correct
+// answer in that account's ledger, if it has been found, so that a solution
+// which only works on some inputs stands out. An account whose session is
+// rejected has the error shown in its row, and the other accounts still run.
+func runAccounts(ctx context.Context, a *app, entry registry.Entry, parts []int) error {
+	accounts, err := a.accounts()
+	if err != nil {
+		return err
+	}
+
+	var (
+		results = make([]accountResult, len(accounts))
+		errs    error
+		wrong   error
+	)
+	for i, acc := range accounts {
+		results[i] = accountResult{Account: acc.Name, Statuses: make([]string, len(parts))}
+
+		fetcher, err := a.accountFetcher(acc)
+		if err != nil {
+			return err
+		}
+		if err := a.checkSessionForInputs(ctx, fetcher, entry.Puzzle); err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("account %s: %w", acc.Name, err))
+			results[i].Results = make([]partResult, len(parts))
+			for j, part := range parts {
+				results[i].Results[j] = partResult{Puzzle: entry.Puzzle, Part: part, Err: err}
+				results[i].Statuses[j] = "failed"
+			}
+			continue
+		}
+
+		res, err := runPuzzle(ctx, a.log.With(zap.String("account", acc.Name)), fetcher, entry, parts)
+		if err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("account %s: %w", acc.Name, err))
+		}
+		results[i].Results = res
+
+		for j, r := range res {
+			status, err := answerStatus(fetcher.Ledger(), r)
+			if err != nil {
+				wrong = multierr.Append(wrong, fmt.Errorf("account %s: %w", acc.Name, err))
+			}
+			results[i].Statuses[j] = status
+		}
+	}
+
+	printAccountResults(a.stdout, parts, results)
+
+	switch {
+	case errs != nil && isFetchError(errs):
+		return fetchError(errs)
+	case errs != nil:
+		return withExitCode(exitSolution, errs)
+	}
+	return withExitCode(exitMismatch, wrong)
+}
+
+// answerStatus describes how the answer compares with what the ledger knows,
+// returning an error if it is known to be wrong.
+func answerStatus(ledger *aoc.Ledger, r partResult) (string, error) {
+	if r.Err != nil {
+		return "failed", nil
+	}
+
+	err := ledger.Check(r.Puzzle, r.Part, r.Answer)
+	switch {
+	case errors.Is(err, aoc.ErrAlreadySolved):
+		return "ok", nil
+	case errors.Is(err, aoc.ErrKnownWrong), errors.Is(err, aoc.ErrOutOfBounds):
+		return "WRONG", fmt.Errorf("%s part %d: %w", r.Puzzle, r.Part, err)
+	}
+	return "unverified", nil
+}
+
+func printAccountResults(w io.Writer, parts []int, results []accountResult) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	header := []string{"ACCOUNT"}
+	for _, p := range parts {
+		header = append(header, fmt.Sprintf("PART %d", p))
+	}
+	_, _ = fmt.Fprintln(tw, strings.Join(header, "\t"))
+
+	for _, res := range results {
+		row := []string{res.Account}
+		for i, r := range res.Results {
+			if r.Err != nil {
+				row = append(row, fmt.Sprintf("(%s: %s)", res.Statuses[i], r.Err))
+				continue
+			}
+			row = append(row, fmt.Sprintf("%s (%s)", r.Answer, res.Statuses[i]))
+		}
+		_, _ = fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	_ = tw.Flush()
+}
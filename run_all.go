@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
+	"github.com/nightmarlin/aoc2022/aoc"
+	"github.com/nightmarlin/aoc2022/registry"
+)
+
+// A partResult is the outcome of running a single part of a single day.
+type partResult struct {
+	Puzzle   aoc.Puzzle
+	Part     int
+	Answer   aoc.Answer
+	Duration time.Duration
+	Err      error
+}
+
+func (r partResult) Status() string {
+	if r.Err != nil {
+		return "failed"
+	}
+	return "ok"
+}
+
+// runAll runs the solution for each entry, with up to parallel days running at
+// once. Failures are collected rather than stopping the run, and returned
+// together once every day has finished. If any input could not be fetched the
+// exit code reflects that, otherwise it reflects the failed solutions.
+func runAll(ctx context.Context, a *app, entries []registry.Entry, parts []int, parallel int) error {
+	if parallel < 1 {
+		return usageErrorf("parallel must be at least 1, got %d", parallel)
+	}
+
+	fetcher, err := a.fetcher()
+	if err != nil {
+		return err
+	}
+	if err := a.checkSessionForInputs(ctx, fetcher, puzzlesOf(entries)...); err != nil {
+		return err
+	}
+
+	var (
+		results = make([][]partResult, len(entries))
+		errs    = make([]error, len(entries))
+
+		sem = make(chan struct{}, parallel)
+		wg  sync.WaitGroup
+	)
+
+	for i := range entries {
+		i := i
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			results[i], errs[i] = runPuzzle(ctx, a.log, fetcher, entries[i], parts)
+		}()
+	}
+	wg.Wait()
+
+	var flat []partResult
+	for i := range results {
+		flat = append(flat, results[i]...)
+	}
+	printSummary(a.stdout, flat)
+
+	err = multierr.Combine(errs...)
+	switch {
+	case err == nil:
+		return nil
+	case isFetchError(err):
+		return fetchError(err)
+	default:
+		return withExitCode(exitSolution, err)
+	}
+}
+
+// runPuzzle fetches the input for the entry's Puzzle and runs the requested
+// parts against it. A failure in one part does not stop the other from running.
+func runPuzzle(
+	ctx context.Context,
+	log *zap.Logger,
+	fetcher aoc.Fetcher,
+	entry registry.Entry,
+	parts []int,
+) ([]partResult, error) {
+	puzzle := entry.Puzzle
+	log = log.With(zap.Stringer("puzzle", puzzle))
+
+	results := make([]partResult, len(parts))
+	for i := range parts {
+		results[i] = partResult{Puzzle: puzzle, Part: parts[i]}
+	}
+
+	input, err := fetcher.FetchInput(ctx, puzzle)
+	if err != nil {
+		err = inputError(puzzle, err)
+		for i := range results {
+			results[i].Err = err
+		}
+		return results, err
+	}
+
+	solution := entry.New(log)
+
+	var errs error
+	for i := range results {
+		start := time.Now()
+		results[i].Answer, results[i].Err = runPart(ctx, solution, results[i].Part, input)
+		results[i].Duration = time.Since(start)
+
+		if results[i].Err != nil {
+			log.Warn("part failed", zap.Int("part", results[i].Part), zap.Error(results[i].Err))
+			errs = multierr.Append(
+				errs,
+				fmt.Errorf("%s part %d: %w", puzzle, results[i].Part, results[i].Err),
+			)
+		}
+	}
+	return results, errs
+}
+
+// isFetchError reports whether any of the errors is a failure to fetch an
+// input, which fetchError gives exitFetch or - when the session is to blame -
+// exitConfig.
+func isFetchError(err error) bool {
+	for _, e := range multierr.Errors(err) {
+		if code := exitCodeFor(e); code == exitFetch || code == exitConfig {
+			return true
+		}
+	}
+	return false
+}
+
+func printSummary(w io.Writer, results []partResult) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "PUZZLE\tPART\tANSWER\tDURATION\tSTATUS")
+
+	for _, r := range results {
+		_, _ = fmt.Fprintf(
+			tw,
+			"%s\t%d\t%s\t%s\t%s\n",
+			r.Puzzle, r.Part, r.Answer, r.Duration.Round(time.Microsecond), r.Status(),
+		)
+	}
+	_ = tw.Flush()
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+
+	"github.com/nightmarlin/aoc2022/registry"
+)
+
+// stdinSource is the -input source that reads from stdin.
+const stdinSource = "-"
+
+// A customInput is an input given on the command line, rather than the puzzle
+// input fetched from AoC.
+type customInput struct {
+	Name string // Name labels the answers for the input.
+	Path string // Path is the file holding the input, or empty for stdin.
+}
+
+// runCustomInputs runs the entry's solution against each input read from
+// source, labelling each answer with the input it is for. A part failing on one
+// input doesn't stop the rest from running.
+func runCustomInputs(ctx context.Context, a *app, entry registry.Entry, parts []int, source string) error {
+	inputs, err := a.readInputs(source)
+	if err != nil {
+		return err
+	}
+
+	var errs error
+	for _, in := range inputs {
+		log := a.log.With(zap.Stringer("puzzle", entry.Puzzle), zap.String("input", in.Name))
+
+		for _, r := range a.runCustomInput(ctx, log, entry.New(log), parts, in) {
+			if r.Err != nil {
+				log.Warn("part failed", zap.Int("part", r.Part), zap.Error(r.Err))
+				errs = multierr.Append(errs, fmt.Errorf("%s part %d: %w", in.Name, r.Part, r.Err))
+				_, _ = fmt.Fprintf(a.stdout, "%s part %d: failed\n", in.Name, r.Part)
+				continue
+			}
+			_, _ = fmt.Fprintf(a.stdout, "%s part %d: %s\n", in.Name, r.Part, r.Answer)
+		}
+	}
+	return withExitCode(exitSolution, errs)
+}
+
+// runCustomInput runs the parts of the Solution against the input. Solutions
+// that can stream their input are given it as it is read, so that inputs of
+// any size can be used, while the rest get it once it has been read in full.
+func (a *app) runCustomInput(
+	ctx context.Context,
+	log *zap.Logger,
+	s registry.Solution,
+	parts []int,
+	in customInput,
+) []partResult {
+	results := make([]partResult, len(parts))
+	for i := range parts {
+		results[i] = partResult{Part: parts[i]}
+	}
+	fail := func(err error) []partResult {
+		for i := range results {
+			results[i].Err = err
+		}
+		return results
+	}
+
+	r := a.stdin
+	if in.Path != "" {
+		f, err := os.Open(in.Path)
+		if err != nil {
+			return fail(fmt.Errorf("failed to open input: %w", err))
+		}
+		defer func() { _ = f.Close() }()
+		r = f
+	}
+
+	if ss, ok := s.(registry.StreamingSolution); ok {
+		log.Info("streaming input to solution")
+		streamParts(ctx, ss, results, r)
+		return results
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fail(fmt.Errorf("failed to read input: %w", err))
+	}
+	for i := range results {
+		log.Info("running solution", zap.Int("part", results[i].Part))
+		results[i].Answer, results[i].Err = runPart(ctx, s, results[i].Part, string(data))
+	}
+	return results
+}
+
+// streamParts runs each part against the input at once, reading the input a
+// single time and handing a copy of it to each part as it goes. A part that
+// finishes early has the rest of its copy discarded, so the others aren't held
+// up waiting for it. Once ctx is done no more of the input is read.
+func streamParts(ctx context.Context, s registry.StreamingSolution, results []partResult, r io.Reader) {
+	var (
+		writers = make([]*io.PipeWriter, len(results))
+		ws      = make([]io.Writer, len(results))
+		wg      sync.WaitGroup
+	)
+	for i := range results {
+		pr, pw := io.Pipe()
+		writers[i], ws[i] = pw, pw
+
+		wg.Add(1)
+		go func(res *partResult, pr *io.PipeReader) {
+			defer wg.Done()
+
+			res.Answer, res.Err = runPartReader(ctx, s, res.Part, pr)
+			if ctx.Err() != nil {
+				// Cancelled, so rather than reading the rest of the input, fail the
+				// copy feeding every part.
+				_ = pr.CloseWithError(ctx.Err())
+				return
+			}
+			_, _ = io.Copy(io.Discard, pr)
+		}(&results[i], pr)
+	}
+
+	_, err := io.Copy(io.MultiWriter(ws...), ctxReader{ctx: ctx, r: r})
+	if err != nil {
+		err = fmt.Errorf("failed to read input: %w", err)
+	}
+	for _, pw := range writers {
+		// A nil error closes the pipe as normal, giving the part io.EOF.
+		_ = pw.CloseWithError(err)
+	}
+	wg.Wait()
+}
+
+// ctxReader reads from r until ctx is done, so that an input that never ends
+// - such as a generator piped to stdin - doesn't outlive a cancelled run.
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r ctxReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
+
+// readInputs finds the inputs in source, which is a file, stdinSource, or a
+// directory whose files are each an input, taken in order of name. Hidden
+// files and subdirectories are skipped.
+func (a *app) readInputs(source string) ([]customInput, error) {
+	if source == stdinSource {
+		return []customInput{{Name: "stdin"}}, nil
+	}
+
+	info, err := os.Stat(source)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return nil, usageErrorf("input %s does not exist", source)
+	case err != nil:
+		return nil, withExitCode(exitFailure, fmt.Errorf("failed to read input: %w", err))
+	case !info.IsDir():
+		return []customInput{{Name: source, Path: source}}, nil
+	}
+
+	entries, err := os.ReadDir(source)
+	if err != nil {
+		return nil, withExitCode(exitFailure, fmt.Errorf("failed to list inputs: %w", err))
+	}
+
+	var inputs []customInput
+	for _, e := range entries {
+		if e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+
+		path := filepath.Join(source, e.Name())
+		inputs = append(inputs, customInput{Name: path, Path: path})
+	}
+
+	if len(inputs) == 0 {
+		return nil, usageErrorf("no inputs found in %s", source)
+	}
+	return inputs, nil
+}
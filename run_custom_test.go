@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/nightmarlin/aoc2022/aoc"
+	"github.com/nightmarlin/aoc2022/aoc/aoctest"
+	"github.com/nightmarlin/aoc2022/registry"
+)
+
+func TestRunInputsFromDirectory(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a-example"), []byte(day01ExampleInput), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b-tiny"), []byte("1\n\n2\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".notes"), []byte("not an input"), 0o600))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "nested"), 0o700))
+
+	var stdout, stderr bytes.Buffer
+	code := runCLI(
+		context.Background(),
+		[]string{"-offline", "-inputs", t.TempDir(), "run", "1", "-input", dir},
+		&stdout,
+		&stderr,
+	)
+	require.Equal(t, exitOK, code, stderr.String())
+
+	example, tiny := filepath.Join(dir, "a-example"), filepath.Join(dir, "b-tiny")
+	assert.Equal(
+		t,
+		example+" part 1: 24000\n"+
+			example+" part 2: 45000\n"+
+			tiny+" part 1: 2\n"+
+			tiny+" part 2: 3\n",
+		stdout.String(),
+	)
+}
+
+func TestReadInputs(t *testing.T) {
+	a := &app{}
+
+	inputs, err := a.readInputs(stdinSource)
+	require.NoError(t, err)
+	assert.Equal(t, []customInput{{Name: "stdin"}}, inputs)
+
+	_, err = a.readInputs(filepath.Join(t.TempDir(), "missing"))
+	assert.Equal(t, exitUsage, exitCodeFor(err))
+
+	_, err = a.readInputs(t.TempDir())
+	assert.Equal(t, exitUsage, exitCodeFor(err), "an empty directory has no inputs")
+}
+
+func TestRunCustomInputStreamsStdin(t *testing.T) {
+	const repeats = 50_000
+
+	entry, ok := registry.Lookup(aoc.Puzzle{Year: 2022, Day: 2})
+	require.True(t, ok)
+
+	a := &app{stdin: aoctest.RepeatReader("A Y\nB X\nC Z\n", repeats)}
+	results := a.runCustomInput(context.Background(), zap.NewNop(), entry.New(zap.NewNop()), []int{1, 2}, customInput{Name: "stdin"})
+
+	require.Len(t, results, 2)
+	for _, r := range results {
+		require.NoError(t, r.Err)
+	}
+	assert.Equal(t, aoc.IntAnswer(15*repeats), results[0].Answer)
+	assert.Equal(t, aoc.IntAnswer(12*repeats), results[1].Answer)
+}
+
+func TestRunCustomInputStopsWhenCancelled(t *testing.T) {
+	entry, ok := registry.Lookup(aoc.Puzzle{Year: 2022, Day: 2})
+	require.True(t, ok)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// The input is far too long to ever finish reading.
+	a := &app{stdin: aoctest.RepeatReader("A Y\nB X\nC Z\n", math.MaxInt)}
+
+	done := make(chan []partResult)
+	go func() {
+		done <- a.runCustomInput(ctx, zap.NewNop(), entry.New(zap.NewNop()), []int{1, 2}, customInput{Name: "stdin"})
+	}()
+
+	select {
+	case results := <-done:
+		require.Len(t, results, 2)
+		for _, r := range results {
+			assert.ErrorIs(t, r.Err, context.DeadlineExceeded)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("run didn't stop after being cancelled")
+	}
+}